@@ -1,6 +1,7 @@
 package golik
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -18,10 +19,21 @@ type CloveContext interface {
 	Loggable
 	CloveExecuter
 	System() Golik
+	Config() Config
 	Parent() (*CloveRef, bool)
 	Self() *CloveRef
 	Children() []*CloveRef
 	Child(name string) (*CloveRef, bool)
+	Watch(ref *CloveRef)
+	Unwatch(ref *CloveRef)
+
+	Tell(ref *CloveRef, payload interface{})
+	Reply(result interface{})
+
+	Become(behavior func(msg Message))
+	Unbecome()
+	Stash()
+	UnstashAll()
 
 	Stop()
 }
@@ -29,24 +41,32 @@ type CloveContext interface {
 type CloveRunnableContext interface {
 	CloveContext
 	Clove() *Clove
-	Messages() <-chan Message
+	Mailbox() Mailbox
+	SystemMessages() <-chan Message
+	NextMessage() (Message, bool)
+	SetCurrentMessage(msg Message)
+	Behavior() func(msg Message)
 
 	RemoveChild(child *CloveRef) bool
 	SetTimer(timer *time.Timer)
 	StopTimer()
+	NotifyStopped()
 }
 
 type ReceiveFunc func(ctx CloveContext) func(msg Message)
-type LifecycleFunc func(ctx CloveContext)
+type LifecycleFunc func(ctx CloveContext) error
 
 type Clove struct {
-	Name       string
-	Receive    ReceiveFunc
-	Handler    HandlerFunc
-	BufferSize uint32
-	Async      bool
-	Timeout    time.Duration
-	RefrestTimeout bool
+	Name               string
+	Receive            ReceiveFunc
+	Handler            HandlerFunc
+	BufferSize         uint32
+	Async              bool
+	Timeout            time.Duration
+	RefrestTimeout     bool
+	Mailbox            MailboxFactory
+	SupervisorStrategy *SupervisorStrategy
+	ConfigKeys         []string
 
 	PreStart  LifecycleFunc
 	PostStart LifecycleFunc
@@ -84,44 +104,69 @@ func EmptyReceive() ReceiveFunc {
 }
 
 func (c *Clove) execute(parent *cloveRunnable, system Golik) (*cloveRunnable, error) {
-	if c.Name == "" {
-		return nil, errors.New("Clove's name is not defined") // TODO create and use name-generator for empty names; like docker
+	if c.Name == "" && parent == nil {
+		return nil, errors.New("Clove's name is not defined")
 	}
 	if c.Receive == nil {
 		return nil, errors.New("Receiver-Function is not defined")
 	}
-	if c.Handler == nil {
-		c.Handler = defaultHandler
+
+	// name and configuration of a path are resolved into a copy, so the same
+	// clove can run at several paths or as anonymous template again and again
+	spec := *c
+	if spec.Name == "" {
+		spec.Name = parent.generateName()
 	}
-	if c.BufferSize == 0 {
-		c.BufferSize = 1000 // TODO configure default buffer-size (in golik.clove) and buffer-size per clove eg. golik.clove.{path-segments}
+	if spec.Handler == nil {
+		spec.Handler = defaultHandler
 	}
 
 	runnable := &cloveRunnable{
-		system:   system,
-		parent:   parent,
-		clove:    c,
-		messages: make(chan Message, c.BufferSize),
+		system:      system,
+		parent:      parent,
+		clove:       &spec,
+		sysMessages: make(chan Message, systemBufferSize),
 	}
-	runnable.log = system.Logger().WithFields(logrus.Fields{
-		"clove": c.Name,
+	runnable.config = newCloveConfig(runnable.path())
+	spec.configure(runnable.config)
+	runnable.mailbox = spec.newMailbox(runnable.config)
+	entry := system.Logger().WithFields(logrus.Fields{
+		"clove": spec.Name,
 		"path":  runnable.path(),
 	})
+	runnable.log = cloveLogger(entry.Logger, entry.Data, runnable.config)
 
-	c.Handler(runnable)
+	if err := spec.Handler(runnable); err != nil {
+		return nil, err
+	}
 
 	return runnable, nil
 }
 
+const systemBufferSize = 64
+
 type cloveRunnable struct {
+	nameCounter  uint64
 	system       Golik
 	parent       *cloveRunnable
 	clove        *Clove
 	children     []*cloveRunnable
-	messages     chan Message
+	mailbox      Mailbox
+	sysMessages  chan Message
 	log          *logrus.Entry
+	logMutex     sync.RWMutex
 	mutex        sync.Mutex
+	sysMutex     sync.RWMutex
 	timeoutTimer *time.Timer
+	stopped      bool
+	watchers     []*CloveRef
+	watching     []*cloveRunnable
+	behavior     func(msg Message)
+	current      *Message
+	stash        []Message
+	unstashed    []Message
+	tasks        []*scheduledTask
+	config       *cloveConfig
 }
 
 func (c *cloveRunnable) at(path string) (*cloveRunnable, bool) {
@@ -173,12 +218,131 @@ func (c *cloveRunnable) path() string {
 	return ppath + "/" + c.clove.Name
 }
 
+func (c *cloveRunnable) Config() Config {
+	return c.config
+}
+
 func (c *cloveRunnable) Clove() *Clove {
 	return c.clove
 }
 
-func (c *cloveRunnable) Messages() <-chan Message {
-	return c.messages
+func (c *cloveRunnable) Mailbox() Mailbox {
+	return c.mailbox
+}
+
+func (c *cloveRunnable) SystemMessages() <-chan Message {
+	return c.sysMessages
+}
+
+func (c *cloveRunnable) NextMessage() (Message, bool) {
+	c.mutex.Lock()
+	if len(c.unstashed) > 0 {
+		msg := c.unstashed[0]
+		c.unstashed = c.unstashed[1:]
+		c.mutex.Unlock()
+		return msg, true
+	}
+	c.mutex.Unlock()
+
+	return c.mailbox.Pop()
+}
+
+func (c *cloveRunnable) SetCurrentMessage(msg Message) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.current = &msg
+}
+
+func (c *cloveRunnable) Tell(ref *CloveRef, payload interface{}) {
+	msg := NewMessage(c.Self(), payload)
+
+	c.mutex.Lock()
+	if c.current != nil {
+		msg = msg.causedBy(*c.current)
+	}
+	c.mutex.Unlock()
+
+	ref.deliver(msg)
+}
+
+func (c *cloveRunnable) Reply(result interface{}) {
+	if c.clove.Async {
+		c.Warn("Reply is not supported by async clove '%v', reply to the message instead", c.path())
+		return
+	}
+
+	c.mutex.Lock()
+	current := c.current
+	c.mutex.Unlock()
+
+	if current == nil {
+		c.Warn("Nothing to reply to in '%v'", c.path())
+		return
+	}
+	current.Reply(result)
+}
+
+// WithMessage binds ctx to msg. Tell and Reply of the returned context refer to
+// msg, even if an async clove handles other messages at the same time.
+func WithMessage(ctx CloveContext, msg Message) CloveContext {
+	return &messageContext{
+		CloveContext: ctx,
+		msg:          msg,
+	}
+}
+
+type messageContext struct {
+	CloveContext
+	msg Message
+}
+
+func (mc *messageContext) Tell(ref *CloveRef, payload interface{}) {
+	ref.deliver(NewMessage(mc.Self(), payload).causedBy(mc.msg))
+}
+
+func (mc *messageContext) Reply(result interface{}) {
+	mc.msg.Reply(result)
+}
+
+func (c *cloveRunnable) Behavior() func(msg Message) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.behavior
+}
+
+func (c *cloveRunnable) Become(behavior func(msg Message)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.behavior = behavior
+}
+
+func (c *cloveRunnable) Unbecome() {
+	c.Become(nil)
+}
+
+func (c *cloveRunnable) Stash() {
+	if c.clove.Async {
+		c.Warn("Stash is not supported by async clove '%v'", c.path())
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.current == nil {
+		c.Warn("Nothing to stash in '%v'", c.path())
+		return
+	}
+	c.stash = append(c.stash, *c.current)
+	c.current = nil
+}
+
+func (c *cloveRunnable) UnstashAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.unstashed = append(c.stash, c.unstashed...)
+	c.stash = nil
 }
 
 func (c *cloveRunnable) System() Golik {
@@ -201,14 +365,18 @@ func (c *cloveRunnable) Parent() (*CloveRef, bool) {
 
 func (c *cloveRunnable) Self() *CloveRef {
 	return &CloveRef{
-		name:     c.clove.Name,
-		messages: c.messages,
-		path:     c.path(),
-		executer: c,
+		name:        c.clove.Name,
+		mailbox:     c.mailbox,
+		sysMessages: c.sysMessages,
+		path:        c.path(),
+		executer:    c,
 	}
 }
 
 func (c *cloveRunnable) Children() []*CloveRef {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	result := make([]*CloveRef, len(c.children))
 	for i, child := range c.children {
 		result[i] = child.Self()
@@ -216,9 +384,18 @@ func (c *cloveRunnable) Children() []*CloveRef {
 	return result
 }
 
+func (c *cloveRunnable) childList() []*cloveRunnable {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result := make([]*cloveRunnable, len(c.children))
+	copy(result, c.children)
+	return result
+}
+
 func (c *cloveRunnable) child(name string) (*cloveRunnable, bool) {
-	for _, child := range c.children {
-		if child.clove.Name == name {
+	for _, child := range c.childList() {
+		if child.clove.Name == name && !child.isStopped() {
 			return child, true
 		}
 	}
@@ -258,15 +435,24 @@ func (c *cloveRunnable) appendChild(cr *cloveRunnable) bool {
 }
 
 func (c *cloveRunnable) RemoveChild(ref *CloveRef) bool {
-	return c.removeChildAt(c.indexOfChild(ref.Path()))
-}
-
-func (c *cloveRunnable) removeChildAt(index int) bool {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	index := -1
+	if runnable, ok := ref.runnable(); ok {
+		for i, child := range c.children {
+			if child == runnable {
+				index = i
+				break
+			}
+		}
+	} else {
+		index = c.indexOfChild(ref.Path())
+	}
+
 	if index >= 0 && index < len(c.children) {
 		c.children = append(c.children[:index], c.children[index+1:]...)
+		return true
 	}
 	return false
 }
@@ -295,14 +481,150 @@ func (c *cloveRunnable) StopTimer() {
 	}
 }
 
+func (c *cloveRunnable) Watch(ref *CloveRef) {
+	watched, ok := ref.runnable()
+	if !ok || watched == c {
+		return
+	}
+
+	if !watched.addWatcher(c.Self()) {
+		c.Self().Tell(Terminated{Ref: ref})
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.watching = append(c.watching, watched)
+}
+
+func (c *cloveRunnable) Unwatch(ref *CloveRef) {
+	watched, ok := ref.runnable()
+	if !ok {
+		return
+	}
+	watched.removeWatcher(c.path())
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, w := range c.watching {
+		if w == watched {
+			c.watching = append(c.watching[:i], c.watching[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *cloveRunnable) addWatcher(watcher *CloveRef) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.stopped {
+		return false
+	}
+	for _, w := range c.watchers {
+		if w.Path() == watcher.Path() {
+			return true
+		}
+	}
+	c.watchers = append(c.watchers, watcher)
+	return true
+}
+
+func (c *cloveRunnable) removeWatcher(path string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, w := range c.watchers {
+		if w.Path() == path {
+			c.watchers = append(c.watchers[:i], c.watchers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *cloveRunnable) addScheduledTask(task *scheduledTask) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.stopped {
+		return false
+	}
+	tasks := c.tasks[:0]
+	for _, t := range c.tasks {
+		if !t.isDone() {
+			tasks = append(tasks, t)
+		}
+	}
+	c.tasks = append(tasks, task)
+	return true
+}
+
+func (c *cloveRunnable) isStopped() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stopped
+}
+
+func (c *cloveRunnable) NotifyStopped() {
+	c.mailbox.Close()
+
+	c.sysMutex.Lock()
+	c.mutex.Lock()
+	c.stopped = true
+	pending := append(c.unstashed, c.stash...)
+	c.unstashed = nil
+	c.stash = nil
+	tasks := c.tasks
+	c.tasks = nil
+	watchers := c.watchers
+	watching := c.watching
+	c.watchers = nil
+	c.watching = nil
+	c.mutex.Unlock()
+	c.sysMutex.Unlock()
+
+	if _, shared := c.mailbox.(sharedMailbox); !shared {
+		for {
+			msg, ok := c.mailbox.Pop()
+			if !ok {
+				break
+			}
+			pending = append(pending, msg)
+		}
+	}
+
+	for _, task := range tasks {
+		task.Cancel()
+	}
+
+	for _, watched := range watching {
+		watched.removeWatcher(c.path())
+	}
+
+	self := c.Self()
+	for _, msg := range pending {
+		PublishDeadLetter(c.system, msg, self, "recipient is stopped")
+	}
+	for _, watcher := range watchers {
+		watcher.Tell(Terminated{Ref: self})
+	}
+
+	c.system.EventStream().Unsubscribe(self)
+	c.system.EventStream().Publish(CloveStopped{Ref: self})
+}
+
 func (c *cloveRunnable) Run(clove *Clove) (*CloveRef, error) {
 	if clove == nil {
 		return nil, errors.New("Clove is nil")
 	}
 
-	for _, child := range c.children {
+	for _, child := range c.childList() {
 		if clove.Name == child.clove.Name {
-			return nil, fmt.Errorf("Clove '%v' already exists", clove.Name)
+			if !child.isStopped() {
+				return nil, fmt.Errorf("Clove '%v' already exists", clove.Name)
+			}
+			c.RemoveChild(child.Self())
+			break
 		}
 	}
 
@@ -316,11 +638,13 @@ func (c *cloveRunnable) Run(clove *Clove) (*CloveRef, error) {
 }
 
 func (c *cloveRunnable) Logger() *logrus.Entry {
+	c.logMutex.RLock()
+	defer c.logMutex.RUnlock()
 	return c.log
 }
 
 func (c *cloveRunnable) Log(entry LogEntry) {
-	HandleLogEntry(c.log, entry)
+	HandleLogEntry(c.Logger(), entry)
 }
 
 func (c *cloveRunnable) Debug(msg string, values ...interface{}) {
@@ -364,10 +688,11 @@ func (c *cloveRunnable) Panic(msg string, values ...interface{}) {
 }
 
 type CloveRef struct {
-	name     string
-	path     string
-	messages chan Message
-	executer CloveExecuter
+	name        string
+	path        string
+	mailbox     Mailbox
+	sysMessages chan Message
+	executer    CloveExecuter
 }
 
 func (cr *CloveRef) Name() string {
@@ -379,38 +704,100 @@ func (cr *CloveRef) Path() string {
 }
 
 func (cr *CloveRef) Length() int {
-	return len(cr.messages)
+	return cr.mailbox.Len()
 }
 
 func (cr *CloveRef) Capacity() int {
-	return cap(cr.messages)
+	return cr.mailbox.Cap()
 }
 
+// Tell sends payload without sender and without causation. Within Receive use
+// ctx.Tell, which sets the sender and carries the correlation of the current
+// message over to the new one.
 func (cr *CloveRef) Tell(payload interface{}) {
-	m := NewMessage(cr, payload)
-	cr.messages <- m
+	cr.deliver(NewMessage(nil, payload))
+}
+
+func (cr *CloveRef) deliver(msg Message) {
+	msg.recipient = cr
+	if _, ok := msg.Payload.(Stop); ok {
+		cr.deliverSystem(msg)
+		return
+	}
+
+	runnable, ok := cr.runnable()
+	if ok && runnable.isStopped() {
+		PublishDeadLetter(runnable.system, msg, cr, "recipient is stopped")
+		return
+	}
+
+	if err := cr.mailbox.Push(msg); err != nil && ok {
+		if merr, isMailboxError := err.(*MailboxError); isMailboxError {
+			PublishDeadLetter(runnable.system, merr.Dropped, cr, merr.Reason)
+		} else {
+			PublishDeadLetter(runnable.system, msg, cr, err.Error())
+		}
+	}
+}
+
+func (cr *CloveRef) runnable() (*cloveRunnable, bool) {
+	if cr == nil {
+		return nil, false
+	}
+	runnable, ok := cr.executer.(*cloveRunnable)
+	return runnable, ok
+}
+
+func (cr *CloveRef) tellSystem(payload interface{}) Message {
+	m := NewRequest(nil, payload)
+	m.recipient = cr
+	cr.deliverSystem(m)
+	return m
+}
+
+func (cr *CloveRef) deliverSystem(msg Message) {
+	runnable, ok := cr.runnable()
+	if !ok {
+		cr.sysMessages <- msg
+		return
+	}
+
+	runnable.sysMutex.RLock()
+	defer runnable.sysMutex.RUnlock()
+	if runnable.isStopped() {
+		switch msg.Payload.(type) {
+		case Stop:
+			msg.Reply(Stopped{})
+		case ChildStopped:
+		default:
+			PublishDeadLetter(runnable.system, msg, cr, "recipient is stopped")
+		}
+		return
+	}
+	cr.sysMessages <- msg
 }
 
 func (cr *CloveRef) Ask(payload interface{}, timeout time.Duration) <-chan interface{} {
 	result := make(chan interface{}, 1)
 
 	go func() {
-		m := NewMessage(cr, payload)
-		cr.messages <- m
-		select {
-		case res := <-m.Result():
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		res, err := cr.AskContext(ctx, payload).Await()
+		if err != nil {
+			result <- err
+		} else {
 			result <- res
-			close(result)
-		case <-time.After(timeout):
-			result <- errors.New("Timeout") // TODO
 		}
+		close(result)
 	}()
 
 	return result
 }
 
 func (cr *CloveRef) AskFunc(payload interface{}, timeout time.Duration) (interface{}, error) {
-	switch result := <- cr.Ask(payload, timeout); result.(type) {
+	switch result := <-cr.Ask(payload, timeout); result.(type) {
 	case error:
 		return nil, result.(error)
 	default:
@@ -419,14 +806,14 @@ func (cr *CloveRef) AskFunc(payload interface{}, timeout time.Duration) (interfa
 }
 
 func (cr *CloveRef) Request(payload interface{}) <-chan interface{} {
-	m := NewMessage(cr, payload)
-	cr.messages <- m
+	m := NewRequest(nil, payload)
+	cr.deliver(m)
 	return m.Result()
 }
 
 func (cr *CloveRef) RequestFunc(payload interface{}) (interface{}, error) {
-	switch result := <- cr.Request(payload); payload.(type) {
-	case error: 
+	switch result := <-cr.Request(payload); result.(type) {
+	case error:
 		return nil, result.(error)
 	default:
 		return result, nil
@@ -434,7 +821,7 @@ func (cr *CloveRef) RequestFunc(payload interface{}) (interface{}, error) {
 }
 
 func (cr *CloveRef) Forward(msg Message) {
-	cr.messages <- msg
+	cr.deliver(msg)
 }
 
 func (cr *CloveRef) Run(clove *Clove) (*CloveRef, error) {
@@ -5,10 +5,42 @@ type Done struct {  }
 type Stop struct {  }
 type Stopped struct{ }
 
+type PoisonPill struct{}
+
+type Kill struct{}
+
 type StopChild struct { }
 
 type ChildStopped struct {
 	Child *CloveRef
+	Error error
+}
+
+type Terminated struct {
+	Ref *CloveRef
+}
+
+type CloveStarted struct {
+	Ref *CloveRef
+}
+
+type CloveStopped struct {
+	Ref *CloveRef
 }
 
 type Timeout struct {}
+
+type Restart struct {}
+
+type Resume struct{}
+
+type ChildFailed struct {
+	Child *CloveRef
+	Error error
+}
+
+// ConfigChanged is delivered to a clove when the golik.clove configuration of
+// its path or one of its ConfigKeys changed.
+type ConfigChanged struct {
+	Keys []string
+}
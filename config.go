@@ -0,0 +1,427 @@
+package golik
+
+import (
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const cloveConfigPrefix = "golik.clove."
+
+// Config resolves keys for a single clove from golik.clove.{path-segments}.{key}.
+// Path segments may use the wildcards of path.Match and '**' for any number of
+// segments; keys directly below golik.clove apply to every clove. The most
+// specific match wins. The keys below golik.clove are collected when a system
+// is created and whenever the config file changes.
+type Config interface {
+	Path() string
+	Key(key string) (string, bool)
+	IsSet(key string) bool
+	Get(key string) interface{}
+	GetString(key string) string
+	GetBool(key string) bool
+	GetInt(key string) int
+	GetFloat64(key string) float64
+	GetDuration(key string) time.Duration
+	GetStringSlice(key string) []string
+}
+
+func ConfigOf(clovePath string) Config {
+	return newCloveConfig(clovePath)
+}
+
+func newCloveConfig(clovePath string) *cloveConfig {
+	return &cloveConfig{path: clovePath}
+}
+
+type cloveConfig struct {
+	path string
+}
+
+func (cc *cloveConfig) Path() string {
+	return cc.path
+}
+
+// cloveSetting is a key below golik.clove split into its segments.
+type cloveSetting struct {
+	key      string
+	segments []string
+}
+
+var (
+	cloveSettingsMutex sync.Mutex
+	cloveSettingsCache []cloveSetting
+	cloveSettingsValid bool
+)
+
+// cloveSettings returns the keys below golik.clove. They are collected once
+// and again after the settings changed, not on every lookup.
+func cloveSettings() []cloveSetting {
+	cloveSettingsMutex.Lock()
+	defer cloveSettingsMutex.Unlock()
+
+	if !cloveSettingsValid {
+		cloveSettingsCache = make([]cloveSetting, 0)
+		for _, k := range viper.AllKeys() {
+			if strings.HasPrefix(k, cloveConfigPrefix) {
+				cloveSettingsCache = append(cloveSettingsCache, cloveSetting{
+					key:      k,
+					segments: strings.Split(strings.TrimPrefix(k, cloveConfigPrefix), "."),
+				})
+			}
+		}
+		cloveSettingsValid = true
+	}
+	return cloveSettingsCache
+}
+
+func invalidateCloveSettings() {
+	cloveSettingsMutex.Lock()
+	defer cloveSettingsMutex.Unlock()
+	cloveSettingsValid = false
+}
+
+func (cc *cloveConfig) Key(key string) (string, bool) {
+	settingsLock.RLock()
+	defer settingsLock.RUnlock()
+	return cc.key(key)
+}
+
+// lookup calls get with the resolved key, the settings are not reloaded
+// meanwhile.
+func (cc *cloveConfig) lookup(key string, get func(k string)) bool {
+	settingsLock.RLock()
+	defer settingsLock.RUnlock()
+	k, ok := cc.key(key)
+	if ok && get != nil {
+		get(k)
+	}
+	return ok
+}
+
+func (cc *cloveConfig) IsSet(key string) bool {
+	return cc.lookup(key, nil)
+}
+
+func (cc *cloveConfig) Get(key string) interface{} {
+	var value interface{}
+	cc.lookup(key, func(k string) { value = viper.Get(k) })
+	return value
+}
+
+func (cc *cloveConfig) GetString(key string) string {
+	var value string
+	cc.lookup(key, func(k string) { value = viper.GetString(k) })
+	return value
+}
+
+func (cc *cloveConfig) GetBool(key string) bool {
+	var value bool
+	cc.lookup(key, func(k string) { value = viper.GetBool(k) })
+	return value
+}
+
+func (cc *cloveConfig) GetInt(key string) int {
+	var value int
+	cc.lookup(key, func(k string) { value = viper.GetInt(k) })
+	return value
+}
+
+func (cc *cloveConfig) GetFloat64(key string) float64 {
+	var value float64
+	cc.lookup(key, func(k string) { value = viper.GetFloat64(k) })
+	return value
+}
+
+func (cc *cloveConfig) GetDuration(key string) time.Duration {
+	var value time.Duration
+	cc.lookup(key, func(k string) { value = viper.GetDuration(k) })
+	return value
+}
+
+func (cc *cloveConfig) GetStringSlice(key string) []string {
+	var value []string
+	cc.lookup(key, func(k string) { value = viper.GetStringSlice(k) })
+	return value
+}
+
+func (cc *cloveConfig) key(key string) (string, bool) {
+	keySegments := strings.Split(strings.ToLower(key), ".")
+	segments := splitPath(strings.ToLower(cc.path))
+
+	best := ""
+	bestScore := -1
+	for _, setting := range cloveSettings() {
+		n := len(setting.segments) - len(keySegments)
+		if n < 0 || !equalSegments(setting.segments[n:], keySegments) {
+			continue
+		}
+		pattern := setting.segments[:n]
+		if n == 0 {
+			pattern = []string{"**"}
+		}
+
+		if score, ok := matchPattern(pattern, segments); ok && score > bestScore {
+			best = setting.key
+			bestScore = score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+func equalSegments(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func splitPath(p string) []string {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(p, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// matchPattern reports whether pattern matches all segments. The score
+// prefers exact segments over globs over '*' over '**'.
+func matchPattern(pattern []string, segments []string) (int, bool) {
+	if len(pattern) == 0 {
+		return 0, len(segments) == 0
+	}
+
+	p := pattern[0]
+	if p == "**" {
+		best, found := -1, false
+		for i := 0; i <= len(segments); i++ {
+			if score, ok := matchPattern(pattern[1:], segments[i:]); ok && score > best {
+				best, found = score, true
+			}
+		}
+		return best, found
+	}
+
+	if len(segments) == 0 {
+		return 0, false
+	}
+	if matched, _ := path.Match(p, segments[0]); !matched {
+		return 0, false
+	}
+	score, ok := matchPattern(pattern[1:], segments[1:])
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case p == "*":
+		return score + 10, true
+	case strings.ContainsAny(p, "*?["):
+		return score + 100, true
+	default:
+		return score + 1000, true
+	}
+}
+
+func parseLogLevel(level string) (logrus.Level, bool) {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		return logrus.DebugLevel, true
+	case "INFO":
+		return logrus.InfoLevel, true
+	case "WARN":
+		return logrus.WarnLevel, true
+	case "ERROR":
+		return logrus.ErrorLevel, true
+	case "FATAL":
+		return logrus.FatalLevel, true
+	case "PANIC":
+		return logrus.PanicLevel, true
+	}
+	return logrus.InfoLevel, false
+}
+
+func parseDirective(directive string) (Directive, bool) {
+	switch strings.ToLower(directive) {
+	case "resume":
+		return ResumeDirective, true
+	case "restart":
+		return RestartDirective, true
+	case "stop":
+		return StopDirective, true
+	case "escalate":
+		return EscalateDirective, true
+	}
+	return RestartDirective, false
+}
+
+func (c *Clove) configure(conf Config) {
+	if conf.IsSet("bufferSize") {
+		c.BufferSize = uint32(conf.GetInt("bufferSize"))
+	}
+	if conf.IsSet("async") {
+		c.Async = conf.GetBool("async")
+	}
+	if conf.IsSet("timeout") {
+		c.Timeout = conf.GetDuration("timeout")
+	}
+	if conf.IsSet("refreshTimeout") {
+		c.RefrestTimeout = conf.GetBool("refreshTimeout")
+	}
+	if c.BufferSize == 0 {
+		c.BufferSize = 1000
+	}
+
+	if conf.IsSet("supervisor.strategy") || conf.IsSet("supervisor.maxRetries") || conf.IsSet("supervisor.within") || conf.IsSet("supervisor.directive") {
+		strategy := DefaultSupervisorStrategy()
+		if c.SupervisorStrategy != nil {
+			copied := *c.SupervisorStrategy
+			strategy = &copied
+		}
+		switch strings.ToLower(conf.GetString("supervisor.strategy")) {
+		case "oneforone":
+			strategy.Kind = OneForOne
+		case "allforone":
+			strategy.Kind = AllForOne
+		}
+		if conf.IsSet("supervisor.maxRetries") {
+			strategy.MaxRetries = conf.GetInt("supervisor.maxRetries")
+		}
+		if conf.IsSet("supervisor.within") {
+			strategy.Within = conf.GetDuration("supervisor.within")
+		}
+		if directive, ok := parseDirective(conf.GetString("supervisor.directive")); ok {
+			strategy.Decider = func(err error) Directive {
+				return directive
+			}
+		}
+		c.SupervisorStrategy = strategy
+	}
+}
+
+// newMailbox creates a fresh mailbox for every run of the clove, either with
+// the Mailbox factory of the clove or with the configured mailbox type.
+func (c *Clove) newMailbox(conf Config) Mailbox {
+	if c.Mailbox != nil {
+		return c.Mailbox()
+	}
+	switch strings.ToLower(conf.GetString("mailbox")) {
+	case "unbounded":
+		return UnboundedMailbox()
+	case "dropnewest":
+		return DropNewestMailbox(int(c.BufferSize))
+	case "dropoldest":
+		return DropOldestMailbox(int(c.BufferSize))
+	case "priority":
+		return PriorityMailbox(nil)
+	}
+	return BlockingMailbox(int(c.BufferSize), conf.GetDuration("mailboxTimeout"))
+}
+
+func cloveLogLevel(conf Config) logrus.Level {
+	if level, ok := parseLogLevel(conf.GetString("logLevel")); ok {
+		return level
+	}
+	return logrus.GetLevel()
+}
+
+type levelLoggerKey struct {
+	base  *logrus.Logger
+	level logrus.Level
+}
+
+var (
+	levelLoggersMutex sync.Mutex
+	levelLoggers      = make(map[levelLoggerKey]*logrus.Logger)
+)
+
+// levelLogger returns a copy of base logging at level. Cloves with the same
+// level share one copy, cloves at the level of base log with base itself.
+func levelLogger(base *logrus.Logger, level logrus.Level) *logrus.Logger {
+	if base.GetLevel() == level {
+		return base
+	}
+
+	levelLoggersMutex.Lock()
+	defer levelLoggersMutex.Unlock()
+	key := levelLoggerKey{base, level}
+	if logger, ok := levelLoggers[key]; ok {
+		return logger
+	}
+	logger := &logrus.Logger{
+		Out:          base.Out,
+		Hooks:        base.Hooks,
+		Formatter:    base.Formatter,
+		ReportCaller: base.ReportCaller,
+		Level:        level,
+		ExitFunc:     base.ExitFunc,
+	}
+	levelLoggers[key] = logger
+	return logger
+}
+
+// cloveLogger logs with fields at the configured level of a clove.
+func cloveLogger(base *logrus.Logger, fields logrus.Fields, conf Config) *logrus.Entry {
+	return levelLogger(base, cloveLogLevel(conf)).WithFields(fields)
+}
+
+func (cc *cloveConfig) affectedBy(key string) bool {
+	if !strings.HasPrefix(key, cloveConfigPrefix) {
+		return false
+	}
+	rest := strings.Split(strings.TrimPrefix(key, cloveConfigPrefix), ".")
+	if len(rest) == 1 || rest[0] == "supervisor" {
+		return true
+	}
+
+	segments := splitPath(strings.ToLower(cc.path))
+	for i := len(rest) - 1; i > 0; i-- {
+		if _, ok := matchPattern(rest[:i], segments); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *cloveRunnable) watchesConfig(key string) bool {
+	for _, prefix := range c.clove.ConfigKeys {
+		prefix = strings.ToLower(prefix)
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// settingsChanged updates the log level of every clove and delivers
+// ConfigChanged to the cloves in scope of the changed keys.
+func (c *cloveRunnable) settingsChanged(keys []string) {
+	c.logMutex.Lock()
+	c.log = cloveLogger(c.system.Logger().Logger, c.log.Data, c.config)
+	c.logMutex.Unlock()
+
+	changed := make([]string, 0)
+	for _, key := range keys {
+		if c.config.affectedBy(key) || c.watchesConfig(key) {
+			changed = append(changed, key)
+		}
+	}
+	if len(changed) > 0 {
+		c.Debug("Configuration of '%v' changed: %v", c.path(), strings.Join(changed, ", "))
+		c.Self().Tell(ConfigChanged{Keys: changed})
+	}
+
+	for _, child := range c.liveChildren() {
+		child.settingsChanged(keys)
+	}
+}
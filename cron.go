@@ -0,0 +1,100 @@
+package golik
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type CronSchedule interface {
+	Next(t time.Time) time.Time
+}
+
+type CronTrigger struct {
+	Job     string
+	Payload interface{}
+	Time    time.Time
+}
+
+func newCron() *Clove {
+	paths := make(map[string]string)
+	tasks := make([]Cancellable, 0)
+
+	return &Clove{
+		Name: "cron",
+		PreStart: func(ctx CloveContext) error {
+			paths = make(map[string]string)
+
+			var jobs map[string]interface{}
+			ReadSettings(func() {
+				jobs = viper.GetStringMap("golik.cron")
+			})
+			for job := range jobs {
+				path, task, err := scheduleCronJob(ctx, job)
+				if err != nil {
+					ctx.Error("Could not schedule cron job '%v': %v", job, err)
+					continue
+				}
+				paths[job] = path
+				tasks = append(tasks, task)
+			}
+			return nil
+		},
+		// a restart runs PostStop as well, the jobs are scheduled again by PreStart
+		PostStop: func(ctx CloveContext) error {
+			for _, task := range tasks {
+				task.Cancel()
+			}
+			tasks = tasks[:0]
+			return nil
+		},
+		Receive: func(ctx CloveContext) func(msg Message) {
+			return func(msg Message) {
+				if trigger, ok := msg.Payload.(CronTrigger); ok {
+					path := paths[trigger.Job]
+					ref, exists := ctx.System().At(path)
+					if !exists {
+						ctx.Warn("Cron job '%v' could not find clove '%v'", trigger.Job, path)
+						return
+					}
+					trigger.Time = time.Now()
+					ref.Tell(trigger)
+				}
+			}
+		},
+	}
+}
+
+func scheduleCronJob(ctx CloveContext, job string) (string, Cancellable, error) {
+	key := func(segment string) string {
+		return fmt.Sprintf("golik.cron.%v.%v", job, segment)
+	}
+
+	var path, spec, zone string
+	var payload interface{}
+	ReadSettings(func() {
+		path = viper.GetString(key("path"))
+		spec = viper.GetString(key("schedule"))
+		zone = viper.GetString(key("timezone"))
+		payload = viper.Get(key("payload"))
+	})
+
+	if path == "" {
+		return "", nil, fmt.Errorf("No path defined for cron job '%v'", job)
+	}
+	if zone != "" {
+		spec = "CRON_TZ=" + zone + " " + spec
+	}
+
+	task, err := ctx.System().Scheduler().ScheduleCron(spec, ctx.Self(), CronTrigger{
+		Job:     job,
+		Payload: payload,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	ctx.Info("Cron job '%v' scheduled with '%v' for '%v'", job, spec, path)
+	return path, task, nil
+}
@@ -0,0 +1,63 @@
+package golik
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronNext(t *testing.T) {
+	tests := []struct {
+		spec string
+		from string
+		want string
+	}{
+		{"CRON_TZ=UTC * * * * * *", "2020-01-01T10:00:00Z", "2020-01-01T10:00:01Z"},
+		{"CRON_TZ=UTC */15 * * * *", "2020-01-01T10:07:30Z", "2020-01-01T10:15:00Z"},
+		{"CRON_TZ=UTC 30 0 */6 * * *", "2020-01-01T10:07:30Z", "2020-01-01T12:00:30Z"},
+		{"CRON_TZ=UTC 0 9-17 * * mon-fri", "2020-01-03T17:30:00Z", "2020-01-06T09:00:00Z"},
+		{"CRON_TZ=UTC 0 0 29 feb *", "2021-01-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+		{"CRON_TZ=UTC 0 0 1 * 1", "2020-01-02T00:00:00Z", "2020-01-06T00:00:00Z"},
+		{"CRON_TZ=UTC 0 0 * * 7", "2020-01-01T00:00:00Z", "2020-01-05T00:00:00Z"},
+		{"CRON_TZ=UTC 0 0 1,15 jan,jul *", "2020-01-15T00:00:00Z", "2020-07-01T00:00:00Z"},
+		{"CRON_TZ=UTC @daily", "2020-12-31T23:59:59Z", "2021-01-01T00:00:00Z"},
+		{"CRON_TZ=UTC @hourly", "2020-01-01T10:00:00Z", "2020-01-01T11:00:00Z"},
+		{"CRON_TZ=Europe/Berlin 0 8 * * *", "2020-06-01T07:00:00Z", "2020-06-02T06:00:00Z"},
+		{"@every 90s", "2020-01-01T10:00:00.5Z", "2020-01-01T10:01:30Z"},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseCron(test.spec)
+		if err != nil {
+			t.Errorf("ParseCron(%q) failed: %v", test.spec, err)
+			continue
+		}
+		from, _ := time.Parse(time.RFC3339Nano, test.from)
+		want, _ := time.Parse(time.RFC3339, test.want)
+		if got := schedule.Next(from); !got.Equal(want) {
+			t.Errorf("ParseCron(%q).Next(%v) = %v, want %v", test.spec, test.from, got.UTC(), want)
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * * *",
+		"* * 24 * * *",
+		"* * * 0 * *",
+		"* * * * 13 *",
+		"* * * * foo *",
+		"*/0 * * * * *",
+		"5-1 * * * * *",
+		"@every 10ms",
+		"@every soon",
+		"CRON_TZ=Nowhere/Never * * * * *",
+		"CRON_TZ=UTC",
+	} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", spec)
+		}
+	}
+}
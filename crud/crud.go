@@ -62,17 +62,17 @@ func CRUD(obj interface{}, conf golik.MinionConfig) *golik.Clove {
 	return &golik.Clove{
 		Name: name,
 		Receive: crudHandler.HandleReceive,
-		PreStart: func(ctx golik.CloveContext) {
-			crudHandler.CallLifeCycle("PreStart", ctx)
+		PreStart: func(ctx golik.CloveContext) error {
+			return crudHandler.CallLifeCycle("PreStart", ctx)
 		},
-		PostStart: func(ctx golik.CloveContext) {
-			crudHandler.CallLifeCycle("PostStart", ctx)
+		PostStart: func(ctx golik.CloveContext) error {
+			return crudHandler.CallLifeCycle("PostStart", ctx)
 		},
-		PreStop: func(ctx golik.CloveContext) {
-			crudHandler.CallLifeCycle("PreStop", ctx)
+		PreStop: func(ctx golik.CloveContext) error {
+			return crudHandler.CallLifeCycle("PreStop", ctx)
 		},
-		PostStop: func(ctx golik.CloveContext) {
-			crudHandler.CallLifeCycle("PostStop", ctx)
+		PostStop: func(ctx golik.CloveContext) error {
+			return crudHandler.CallLifeCycle("PostStop", ctx)
 		},
 		Async: conf.Async,
 		BufferSize: conf.BufferSize,
@@ -92,8 +92,8 @@ type crudHandler struct {
 	mutex sync.Mutex
 }
 
-func (ch *crudHandler) CallLifeCycle(methodName string, ctx golik.CloveContext) {
-	golik.CallLifeCycle(ch.crud, methodName, ctx)
+func (ch *crudHandler) CallLifeCycle(methodName string, ctx golik.CloveContext) error {
+	return golik.CallLifeCycle(ch.crud, methodName, ctx)
 }
 
 func handleCreateCommand(ccmd CreateCommand, crudminion interface{}, ctx golik.CloveContext) (interface{}, error) {
@@ -143,6 +143,7 @@ func (ch *crudHandler) HandleReceive(ctx golik.CloveContext) func(golik.Message)
 			defer ch.mutex.Unlock()
 		}
 
+		ctx := golik.WithMessage(ctx, msg)
 		msgcontent := msg.Payload
 		switch msgcontent.(type) {
 		case CreateCommand:
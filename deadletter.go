@@ -0,0 +1,56 @@
+package golik
+
+import "fmt"
+
+type DeadLetter struct {
+	Message   Message
+	Recipient *CloveRef
+	Reason    string
+}
+
+type SubscribeDeadLetters struct {
+	Subscriber *CloveRef
+}
+
+type UnsubscribeDeadLetters struct {
+	Subscriber *CloveRef
+}
+
+func PublishDeadLetter(system Golik, msg Message, recipient *CloveRef, reason string) {
+	if msg.awaited() {
+		msg.Reply(fmt.Errorf("Message could not be delivered to '%v': %v", recipient.Path(), reason))
+	}
+
+	dl := system.DeadLetters()
+	if dl == nil || dl.Path() == recipient.Path() {
+		system.Warn("Dead letter %T to '%v': %v", msg.Payload, recipient.Path(), reason)
+		return
+	}
+	dl.Tell(DeadLetter{
+		Message:   msg,
+		Recipient: recipient,
+		Reason:    reason,
+	})
+}
+
+func newDeadLetters() *Clove {
+	return &Clove{
+		Name: "deadLetters",
+		Receive: func(ctx CloveContext) func(msg Message) {
+			return func(msg Message) {
+				switch payload := msg.Payload.(type) {
+				case DeadLetter:
+					ctx.Warn("Dead letter %T [id=%v, correlation=%v] to '%v': %v", payload.Message.Payload, payload.Message.ID(), payload.Message.CorrelationID(), payload.Recipient.Path(), payload.Reason)
+					if _, nested := payload.Message.Payload.(DeadLetter); nested {
+						return
+					}
+					ctx.System().EventStream().Publish(payload)
+				case SubscribeDeadLetters:
+					ctx.System().EventStream().Subscribe(payload.Subscriber, DeadLetter{})
+				case UnsubscribeDeadLetters:
+					ctx.System().EventStream().Unsubscribe(payload.Subscriber, DeadLetter{})
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,153 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+type Entity interface {
+	EntityID() string
+}
+
+// Passivate can be sent by an entity to its region to be stopped; messages
+// arriving meanwhile are buffered and delivered to the next incarnation. The
+// region identifies the entity by the sender, so Passivate has to be sent with
+// ctx.Tell(region, Passivate{}) from within the entity.
+type Passivate struct{}
+
+// passivateIdle triggers the passivation of idle entities, ticks scheduled by
+// an earlier run of the region are ignored
+type passivateIdle struct {
+	run *regionRun
+}
+
+type regionRun struct {
+	started time.Time
+}
+
+type Config struct {
+	Name    string
+	Timeout time.Duration
+	Factory func(id string) *golik.Clove
+}
+
+func Region(conf Config) *golik.Clove {
+	return &golik.Clove{
+		Name: conf.Name,
+		Receive: func(ctx golik.CloveContext) func(msg golik.Message) {
+			lastSeen := make(map[string]time.Time)
+			passivating := make(map[string]bool)
+			buffers := make(map[string][]golik.Message)
+
+			run := &regionRun{started: time.Now()}
+			interval := conf.Timeout / 2
+			if interval < 10*time.Millisecond {
+				interval = 10 * time.Millisecond
+			}
+			schedule := func() {
+				if conf.Timeout > 0 {
+					ctx.System().Scheduler().ScheduleOnce(interval, ctx.Self(), passivateIdle{run})
+				}
+			}
+			schedule()
+
+			spawn := func(name string, id string) (*golik.CloveRef, error) {
+				if conf.Factory == nil {
+					return nil, errors.New("Entity factory is not defined")
+				}
+				c := conf.Factory(id)
+				if c == nil {
+					return nil, fmt.Errorf("Entity factory returned no clove for '%v'", id)
+				}
+				c.Name = name
+				ref, err := ctx.Run(c)
+				if err != nil {
+					return nil, err
+				}
+				ctx.Watch(ref)
+				ctx.Debug("Entity '%v' started", id)
+				return ref, nil
+			}
+
+			deliver := func(name string, id string, msg golik.Message) {
+				if passivating[name] {
+					buffers[name] = append(buffers[name], msg)
+					return
+				}
+
+				ref, ok := ctx.Child(name)
+				if !ok {
+					var err error
+					if ref, err = spawn(name, id); err != nil {
+						ctx.Error("Could not start entity '%v': %v", id, err)
+						golik.PublishDeadLetter(ctx.System(), msg, ctx.Self(), err.Error())
+						return
+					}
+				}
+				lastSeen[name] = time.Now()
+				ref.Forward(msg)
+			}
+
+			passivate := func(name string) {
+				if passivating[name] {
+					return
+				}
+				if ref, ok := ctx.Child(name); ok {
+					ctx.Debug("Passivate entity '%v'", name)
+					passivating[name] = true
+					ref.Tell(golik.PoisonPill{})
+				}
+				delete(lastSeen, name)
+			}
+
+			return func(msg golik.Message) {
+				switch payload := msg.Payload.(type) {
+				case Entity:
+					id := payload.EntityID()
+					deliver(url.PathEscape(id), id, msg)
+				case Passivate:
+					if sender, ok := msg.Sender(); ok {
+						passivate(sender.Name())
+					}
+				case passivateIdle:
+					if payload.run != run {
+						return
+					}
+					for name, seen := range lastSeen {
+						if time.Since(seen) >= conf.Timeout {
+							passivate(name)
+						}
+					}
+					schedule()
+				case golik.Terminated:
+					name := payload.Ref.Name()
+					if _, alive := ctx.Child(name); alive {
+						// an earlier incarnation, the entity was started again
+						return
+					}
+					delete(passivating, name)
+					delete(lastSeen, name)
+
+					buffered := buffers[name]
+					delete(buffers, name)
+					if len(buffered) > 0 {
+						id, err := url.PathUnescape(name)
+						if err != nil {
+							id = name
+						}
+						for _, m := range buffered {
+							deliver(name, id, m)
+						}
+					}
+				case golik.ConfigChanged:
+				default:
+					golik.PublishDeadLetter(ctx.System(), msg, ctx.Self(), fmt.Sprintf("%T is not an entity message", msg.Payload))
+				}
+			}
+		},
+	}
+}
@@ -1,5 +1,9 @@
 package golik
 
+import "errors"
+
+var ErrKilled = errors.New("Clove was killed")
+
 type Error struct {
 	Message string `json:"error"`
 	Code string `json:"code,omitempty"`
@@ -0,0 +1,123 @@
+package golik
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// EventStream delivers published events to every clove subscribed to the
+// type of the event or to an interface the event implements. A classifier is
+// either a reflect.Type, a pointer to an interface like (*error)(nil), or a
+// sample value of the type.
+type EventStream interface {
+	Subscribe(ref *CloveRef, classifier interface{}) error
+	Unsubscribe(ref *CloveRef, classifiers ...interface{})
+	Publish(event interface{})
+}
+
+func newEventStream() *eventStream {
+	return &eventStream{
+		subscriptions: make(map[reflect.Type]map[string]*CloveRef),
+	}
+}
+
+type eventStream struct {
+	mutex         sync.RWMutex
+	subscriptions map[reflect.Type]map[string]*CloveRef
+}
+
+func classify(classifier interface{}) (reflect.Type, error) {
+	if classifier == nil {
+		return nil, errors.New("Classifier is nil")
+	}
+	if t, ok := classifier.(reflect.Type); ok {
+		return t, nil
+	}
+	t := reflect.TypeOf(classifier)
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+		return t.Elem(), nil
+	}
+	return t, nil
+}
+
+func (es *eventStream) Subscribe(ref *CloveRef, classifier interface{}) error {
+	if ref == nil {
+		return errors.New("Subscriber is nil")
+	}
+	t, err := classify(classifier)
+	if err != nil {
+		return err
+	}
+
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+	subscribers, ok := es.subscriptions[t]
+	if !ok {
+		subscribers = make(map[string]*CloveRef)
+		es.subscriptions[t] = subscribers
+	}
+	subscribers[ref.Path()] = ref
+	return nil
+}
+
+func (es *eventStream) Unsubscribe(ref *CloveRef, classifiers ...interface{}) {
+	if ref == nil {
+		return
+	}
+
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+	if len(classifiers) == 0 {
+		for t, subscribers := range es.subscriptions {
+			delete(subscribers, ref.Path())
+			if len(subscribers) == 0 {
+				delete(es.subscriptions, t)
+			}
+		}
+		return
+	}
+	for _, classifier := range classifiers {
+		if t, err := classify(classifier); err == nil {
+			if subscribers, ok := es.subscriptions[t]; ok {
+				delete(subscribers, ref.Path())
+				if len(subscribers) == 0 {
+					delete(es.subscriptions, t)
+				}
+			}
+		}
+	}
+}
+
+func (es *eventStream) subscribers(t reflect.Type) []*CloveRef {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	result := make([]*CloveRef, 0)
+	for st, subscribers := range es.subscriptions {
+		if st != t && (st.Kind() != reflect.Interface || !t.Implements(st)) {
+			continue
+		}
+		for path, ref := range subscribers {
+			if !seen[path] {
+				seen[path] = true
+				result = append(result, ref)
+			}
+		}
+	}
+	return result
+}
+
+func (es *eventStream) Publish(event interface{}) {
+	if event == nil {
+		return
+	}
+	for _, ref := range es.subscribers(reflect.TypeOf(event)) {
+		if runnable, ok := ref.runnable(); ok && runnable.isStopped() {
+			es.Unsubscribe(ref)
+			continue
+		}
+		ref.Tell(event)
+	}
+}
@@ -0,0 +1,243 @@
+package fsm
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+type CurrentState struct{}
+
+type StateInfo struct {
+	Name string
+	Data interface{}
+}
+
+type Handler func(ctx golik.CloveContext, msg golik.Message, data interface{}) Next
+
+type TransitionHook func(ctx golik.CloveContext, from string, to string, data interface{})
+
+// Next is returned by a handler and tells the machine what to do after the
+// message was handled, e.g. Goto("open").Using(data).Replying(result).
+type Next struct {
+	state    string
+	stay     bool
+	stop     bool
+	data     interface{}
+	hasData  bool
+	reply    interface{}
+	hasReply bool
+}
+
+func Goto(state string) Next {
+	return Next{state: state}
+}
+
+func Stay() Next {
+	return Next{stay: true}
+}
+
+func Stop() Next {
+	return Next{stay: true, stop: true}
+}
+
+func (n Next) Using(data interface{}) Next {
+	n.data = data
+	n.hasData = true
+	return n
+}
+
+func (n Next) Replying(result interface{}) Next {
+	n.reply = result
+	n.hasReply = true
+	return n
+}
+
+// timeoutToken identifies one armed state timeout, a timeout armed by an
+// earlier state or an earlier run of the clove is ignored
+type timeoutToken struct {
+	state string
+}
+
+type stateTimeout struct {
+	token *timeoutToken
+}
+
+type Builder struct {
+	name        string
+	initial     string
+	initialData interface{}
+	states      map[string]*StateBuilder
+	transitions []TransitionHook
+	unhandled   Handler
+}
+
+func New(name string) *Builder {
+	return &Builder{
+		name:   name,
+		states: make(map[string]*StateBuilder),
+	}
+}
+
+func (b *Builder) StartWith(state string, data interface{}) *Builder {
+	b.initial = state
+	b.initialData = data
+	return b
+}
+
+func (b *Builder) When(state string) *StateBuilder {
+	if sb, ok := b.states[state]; ok {
+		return sb
+	}
+	sb := &StateBuilder{
+		name:     state,
+		handlers: make(map[reflect.Type]Handler),
+	}
+	b.states[state] = sb
+	return sb
+}
+
+func (b *Builder) OnTransition(hook TransitionHook) *Builder {
+	b.transitions = append(b.transitions, hook)
+	return b
+}
+
+func (b *Builder) WhenUnhandled(handler Handler) *Builder {
+	b.unhandled = handler
+	return b
+}
+
+type interfaceHandler struct {
+	itype   reflect.Type
+	handler Handler
+}
+
+type StateBuilder struct {
+	name       string
+	timeout    time.Duration
+	handlers   map[reflect.Type]Handler
+	interfaces []interfaceHandler
+}
+
+// Timeout sends golik.Timeout{} to the handlers of the state if no message
+// was handled within d while the machine stays in the state.
+func (sb *StateBuilder) Timeout(d time.Duration) *StateBuilder {
+	sb.timeout = d
+	return sb
+}
+
+// On registers handler for payloads of the type of sample. A pointer to an
+// interface, e.g. (*fmt.Stringer)(nil), matches every payload implementing it.
+func (sb *StateBuilder) On(sample interface{}, handler Handler) *StateBuilder {
+	t := reflect.TypeOf(sample)
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+		sb.interfaces = append(sb.interfaces, interfaceHandler{itype: t.Elem(), handler: handler})
+		return sb
+	}
+	sb.handlers[t] = handler
+	return sb
+}
+
+func (sb *StateBuilder) handler(payload interface{}) (Handler, bool) {
+	t := reflect.TypeOf(payload)
+	if t == nil {
+		return nil, false
+	}
+	if handler, ok := sb.handlers[t]; ok {
+		return handler, true
+	}
+	for _, ih := range sb.interfaces {
+		if t.Implements(ih.itype) {
+			return ih.handler, true
+		}
+	}
+	return nil, false
+}
+
+func (b *Builder) Build() *golik.Clove {
+	return &golik.Clove{
+		Name: b.name,
+		Receive: func(ctx golik.CloveContext) func(msg golik.Message) {
+			state := b.initial
+			data := b.initialData
+			var token *timeoutToken
+			var timer golik.Cancellable
+
+			arm := func() {
+				token = &timeoutToken{state: state}
+				if timer != nil {
+					timer.Cancel()
+					timer = nil
+				}
+				if sb, ok := b.states[state]; ok && sb.timeout > 0 {
+					timer = ctx.System().Scheduler().ScheduleOnce(sb.timeout, ctx.Self(), stateTimeout{token: token})
+				}
+			}
+
+			apply := func(msg golik.Message, next Next) {
+				if next.hasData {
+					data = next.data
+				}
+				if next.hasReply {
+					msg.Reply(next.reply)
+				}
+				if !next.stay {
+					if _, ok := b.states[next.state]; !ok {
+						ctx.Error("Transition from '%v' to unknown state '%v'", state, next.state)
+						arm()
+						return
+					}
+					from := state
+					state = next.state
+					ctx.Debug("Transition from '%v' to '%v'", from, state)
+					for _, hook := range b.transitions {
+						hook(ctx, from, state, data)
+					}
+				}
+				if next.stop {
+					if timer != nil {
+						timer.Cancel()
+					}
+					ctx.Stop()
+					return
+				}
+				arm()
+			}
+
+			handle := func(msg golik.Message) {
+				if sb, ok := b.states[state]; ok {
+					if handler, ok := sb.handler(msg.Payload); ok {
+						apply(msg, handler(ctx, msg, data))
+						return
+					}
+				}
+				if b.unhandled != nil {
+					apply(msg, b.unhandled(ctx, msg, data))
+					return
+				}
+				if _, ok := msg.Payload.(golik.ConfigChanged); ok {
+					return
+				}
+				golik.PublishDeadLetter(ctx.System(), msg, ctx.Self(), fmt.Sprintf("unhandled in state '%v'", state))
+			}
+
+			arm()
+
+			return func(msg golik.Message) {
+				switch payload := msg.Payload.(type) {
+				case CurrentState:
+					msg.Reply(StateInfo{Name: state, Data: data})
+				case stateTimeout:
+					if payload.token == token {
+						timer = nil
+						handle(golik.NewMessage(nil, golik.Timeout{}))
+					}
+				default:
+					handle(msg)
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package golik
+
+import (
+	"context"
+	"sync"
+)
+
+type Future interface {
+	Done() <-chan struct{}
+	Await() (interface{}, error)
+	OnComplete(f func(result interface{}, err error))
+	Map(f func(result interface{}) (interface{}, error)) Future
+	PipeTo(ref *CloveRef)
+}
+
+func newFuture() *future {
+	return &future{
+		done: make(chan struct{}),
+	}
+}
+
+type future struct {
+	mutex     sync.Mutex
+	done      chan struct{}
+	completed bool
+	result    interface{}
+	err       error
+	callbacks []func(result interface{}, err error)
+}
+
+func (f *future) complete(result interface{}, err error) bool {
+	f.mutex.Lock()
+	if f.completed {
+		f.mutex.Unlock()
+		return false
+	}
+	f.completed = true
+	f.result = result
+	f.err = err
+	callbacks := f.callbacks
+	f.callbacks = nil
+	close(f.done)
+	f.mutex.Unlock()
+
+	for _, callback := range callbacks {
+		go callback(result, err)
+	}
+	return true
+}
+
+func (f *future) completeWith(result interface{}) bool {
+	if err, ok := result.(error); ok {
+		return f.complete(nil, err)
+	}
+	return f.complete(result, nil)
+}
+
+func (f *future) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f *future) Await() (interface{}, error) {
+	<-f.done
+	return f.result, f.err
+}
+
+func (f *future) OnComplete(callback func(result interface{}, err error)) {
+	f.mutex.Lock()
+	if !f.completed {
+		f.callbacks = append(f.callbacks, callback)
+		f.mutex.Unlock()
+		return
+	}
+	f.mutex.Unlock()
+
+	go callback(f.result, f.err)
+}
+
+func (f *future) Map(mapper func(result interface{}) (interface{}, error)) Future {
+	mapped := newFuture()
+	f.OnComplete(func(result interface{}, err error) {
+		if err != nil {
+			mapped.complete(nil, err)
+			return
+		}
+		mapped.complete(mapper(result))
+	})
+	return mapped
+}
+
+func (f *future) PipeTo(ref *CloveRef) {
+	f.OnComplete(func(result interface{}, err error) {
+		if err != nil {
+			ref.Tell(err)
+			return
+		}
+		ref.Tell(result)
+	})
+}
+
+func (cr *CloveRef) AskContext(ctx context.Context, payload interface{}) Future {
+	f := newFuture()
+	m := NewRequest(nil, payload)
+	cr.deliver(m)
+
+	go func() {
+		select {
+		case res := <-m.Result():
+			f.completeWith(res)
+		case <-ctx.Done():
+			f.complete(nil, ctx.Err())
+		}
+	}()
+
+	return f
+}
@@ -3,6 +3,7 @@ module github.com/ioswarm/golik
 go 1.14
 
 require (
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/gorilla/mux v1.8.0
 	github.com/sirupsen/logrus v1.6.0
 	github.com/spf13/viper v1.7.1
@@ -1,89 +1,359 @@
 package golik
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
-type HandlerFunc func(ctx CloveRunnableContext)
+type HandlerFunc func(ctx CloveRunnableContext) error
 
-func defaultHandler(ctx CloveRunnableContext) {
-	ctx.Debug("PreStart '%v'", ctx.Self().Name())
-	if ctx.Clove().PreStart != nil {
-		ctx.Clove().PreStart(ctx)
+type cloveFailure struct {
+	err error
+	msg Message
+}
+
+func callLifecycle(f LifecycleFunc, ctx CloveContext) error {
+	if f == nil {
+		return nil
+	}
+	var err error
+	if perr := safeCall(func() { err = f(ctx) }); perr != nil {
+		return perr
 	}
+	return err
+}
+
+func defaultHandler(ctx CloveRunnableContext) error {
+	var receiveFunc func(msg Message)
+	failures := make(map[string][]time.Time)
+	stopping := false
+	suspended := false
+	escalated := make([]*CloveRef, 0)
+	done := make(chan struct{})
+	var inflight sync.WaitGroup
 
 	timeoutFunc := func(t time.Time) {
 		ctx.Self().Tell(Timeout{})
 	}
 
 	refreshTimer := func() {
-		 if ctx.Clove().Timeout > 0 {
+		if ctx.Clove().Timeout > 0 {
 			timer := ctx.System().NewTimer(ctx.Clove().Timeout, timeoutFunc)
 			ctx.SetTimer(timer)
 		}
 	}
 
-	refreshTimer()
+	start := func() error {
+		ctx.Debug("PreStart '%v'", ctx.Self().Name())
+		if err := callLifecycle(ctx.Clove().PreStart, ctx); err != nil {
+			return err
+		}
 
-	receiveFunc := ctx.Clove().Receive(ctx)
+		if err := safeCall(func() { receiveFunc = ctx.Clove().Receive(ctx) }); err != nil {
+			return err
+		}
 
-	go func() {
-		for {
-			msg, ok := <- ctx.Messages()
-			if !ok {
-				ctx.Debug("Clove channel is closed ... stop message-loop")
-				break
+		refreshTimer()
+		return nil
+	}
+
+	haltChildren := func() {
+		cl := ctx.Children()
+		results := make([]Message, len(cl))
+		for i, child := range cl {
+			results[i] = child.tellSystem(Stop{})
+		}
+		for i, child := range cl {
+			<-results[i].Result()
+			ctx.RemoveChild(child)
+		}
+	}
+
+	// teardown runs PreStop, stops the children and runs PostStop; it returns
+	// the first error of the hooks
+	teardown := func() error {
+		var failure error
+		ctx.Debug("PreStop '%v'", ctx.Self().Name())
+		if err := callLifecycle(ctx.Clove().PreStop, ctx); err != nil {
+			ctx.Error("PreStop of '%v' failed: %v", ctx.Self().Path(), err)
+			failure = err
+		}
+
+		ctx.StopTimer()
+		haltChildren()
+
+		ctx.Debug("PostStop '%v'", ctx.Self().Name())
+		if err := callLifecycle(ctx.Clove().PostStop, ctx); err != nil {
+			ctx.Error("PostStop of '%v' failed: %v", ctx.Self().Path(), err)
+			if failure == nil {
+				failure = err
+			}
+		}
+		return failure
+	}
+
+	stop := func(msg Message) {
+		if stopping {
+			go func() {
+				<-done
+				msg.Reply(Stopped{})
+			}()
+			return
+		}
+		stopping = true
+
+		go func() {
+			inflight.Wait()
+
+			// a failing PreStop or PostStop does not keep the clove alive, the
+			// first error is reported to the parent
+			failure := teardown()
+
+			ctx.NotifyStopped()
+			close(done)
+			msg.Reply(Stopped{})
+			if parent, ok := ctx.Parent(); ok {
+				parent.tellSystem(ChildStopped{Child: ctx.Self(), Error: failure})
 			}
-			switch payload := msg.Payload; payload.(type) {
-			case ChildStopped:
-				cs := payload.(ChildStopped)
-				if cs.Child != nil {
-					ctx.RemoveChild(cs.Child)
+		}()
+	}
+
+	// restart tears the failed instance down like a stop, including its
+	// children, and starts it again with the mailbox kept
+	restart := func() {
+		ctx.Info("Restart '%v'", ctx.Self().Name())
+		teardown()
+		ctx.Unbecome()
+		ctx.UnstashAll()
+		if err := start(); err != nil {
+			ctx.Error("Failure while restarting '%v', stop clove: %v", ctx.Self().Name(), err)
+			stop(NewMessage(nil, Stop{}))
+			return
+		}
+
+		ctx.Debug("PostStart '%v'", ctx.Self().Name())
+		if err := callLifecycle(ctx.Clove().PostStart, ctx); err != nil {
+			ctx.Error("PostStart of '%v' failed, stop clove: %v", ctx.Self().Path(), err)
+			stop(NewMessage(nil, Stop{}))
+		}
+	}
+
+	// direct sends the decision of a supervisor to a suspended child
+	direct := func(child *CloveRef, directive Directive) {
+		switch directive {
+		case ResumeDirective:
+			child.tellSystem(Resume{})
+		case RestartDirective:
+			child.tellSystem(Restart{})
+		case StopDirective:
+			child.tellSystem(Stop{})
+		}
+	}
+
+	apply := func(directive Directive) {
+		ctx.Debug("Apply directive %v to '%v'", directive, ctx.Self().Name())
+		suspended = false
+		switch directive {
+		case RestartDirective:
+			restart()
+		case StopDirective:
+			stop(NewMessage(nil, Stop{}))
+		}
+
+		// children which escalated their failure share the fate of this clove,
+		// a restart or stop tears them down anyway
+		if directive == ResumeDirective {
+			for _, child := range escalated {
+				direct(child, directive)
+			}
+		}
+		escalated = nil
+	}
+
+	// escalate suspends the clove and reports the failure to the parent without
+	// waiting, the decision arrives as Resume, Restart or Stop system message
+	escalate := func(err error) {
+		parent, ok := ctx.Parent()
+		if !ok {
+			apply(RestartDirective)
+			return
+		}
+		suspended = true
+		parent.tellSystem(ChildFailed{Child: ctx.Self(), Error: err})
+	}
+
+	fail := func(err error, msg Message) {
+		ctx.Error("Failure while receiving %T [id=%v, correlation=%v] in '%v': %v", msg.Payload, msg.ID(), msg.CorrelationID(), ctx.Self().Path(), err)
+		if msg.awaited() {
+			msg.Reply(err)
+		} else if _, killed := msg.Payload.(Kill); !killed {
+			PublishDeadLetter(ctx.System(), msg, ctx.Self(), err.Error())
+		}
+
+		if !suspended && !stopping {
+			escalate(err)
+		}
+	}
+
+	supervise := func(cf ChildFailed) {
+		strategy := ctx.Clove().SupervisorStrategy
+		if strategy == nil {
+			strategy = DefaultSupervisorStrategy()
+		}
+
+		directive := strategy.decide(failures, cf.Child.Name(), cf.Error)
+		ctx.Debug("Child '%v' failed with '%v', directive %v", cf.Child.Name(), cf.Error, directive)
+		if directive == EscalateDirective {
+			escalated = append(escalated, cf.Child)
+			if !suspended {
+				escalate(cf.Error)
+			}
+			return
+		}
+
+		direct(cf.Child, directive)
+		if strategy.Kind == AllForOne && directive != ResumeDirective {
+			// restarts and stops, also the stop after MaxRetries is exceeded,
+			// apply to every child
+			for _, sibling := range ctx.Children() {
+				if sibling.Path() != cf.Child.Path() {
+					direct(sibling, directive)
 				}
-			case Stop:
-				go func() {
-					ctx.Debug("PreStop '%v'", ctx.Self().Name())
-					if ctx.Clove().PreStop != nil {
-						ctx.Clove().PreStop(ctx)
-					}
-
-					ctx.StopTimer()
-
-					cl := make([]*CloveRef, len(ctx.Children()))
-					copy(cl, ctx.Children())
-					for _, child := range cl {
-						<- child.Request(Stop{})
-						ctx.RemoveChild(child)
-					}
-
-					ctx.Debug("PostStop '%v'", ctx.Self().Name())
-					if ctx.Clove().PostStop != nil {
-						ctx.Clove().PostStop(ctx)
-					}
+			}
+		}
+	}
 
+	receive := func(msg Message) {
+		f := receiveFunc
+		if behavior := ctx.Behavior(); behavior != nil {
+			f = behavior
+		}
+		// async cloves handle messages concurrently, there is no current message
+		if ctx.Clove().Async {
+			inflight.Add(1)
+			go func() {
+				defer inflight.Done()
+				if err := safeCall(func() { f(msg) }); err != nil {
+					ctx.Self().tellSystem(cloveFailure{err, msg})
+				}
+			}()
+			return
+		}
+		ctx.SetCurrentMessage(msg)
+		if err := safeCall(func() { f(msg) }); err != nil {
+			fail(err, msg)
+		}
+	}
+
+	handleSystem := func(msg Message) {
+		switch payload := msg.Payload.(type) {
+		case ChildFailed:
+			if stopping {
+				payload.Child.tellSystem(Stop{})
+				return
+			}
+			supervise(payload)
+		case ChildStopped:
+			if payload.Error != nil {
+				ctx.Warn("Child '%v' stopped with failure: %v", payload.Child.Path(), payload.Error)
+			}
+			if payload.Child != nil {
+				delete(failures, payload.Child.Name())
+				ctx.RemoveChild(payload.Child)
+			}
+		case cloveFailure:
+			fail(payload.err, payload.msg)
+		case Restart:
+			if !stopping {
+				apply(RestartDirective)
+			}
+		case Resume:
+			if !stopping {
+				apply(ResumeDirective)
+			}
+		case Stop:
+			stop(msg)
+		}
+	}
+
+	handle := func(msg Message) {
+		switch msg.Payload.(type) {
+		case PoisonPill:
+			stop(msg)
+		case Kill:
+			fail(ErrKilled, msg)
+		case Timeout:
+			receive(msg)
+			stop(NewMessage(nil, Stop{}))
+		default:
+			if ctx.Clove().RefrestTimeout {
+				refreshTimer()
+			}
+
+			receive(msg)
+		}
+	}
+
+	// drain answers the system messages left when the loop exits, nothing
+	// is queued for a stopped clove afterwards
+	drain := func() {
+		for {
+			select {
+			case msg := <-ctx.SystemMessages():
+				if _, ok := msg.Payload.(Stop); ok {
 					msg.Reply(Stopped{})
-					if parent, ok := ctx.Parent(); ok {
-						parent.Tell(ChildStopped{ctx.Self()})
-					}
-
-					return
-				}()
-			case Timeout:
-				receiveFunc(msg)
-				ctx.Stop()
-			default:
-				if ctx.Clove().RefrestTimeout {
-					refreshTimer()
 				}
-				
-				if ctx.Clove().Async {
-					go receiveFunc(msg)
-				} else {
-					receiveFunc(msg)
+			default:
+				return
+			}
+		}
+	}
+
+	if err := start(); err != nil {
+		ctx.Error("PreStart of '%v' failed: %v", ctx.Self().Path(), err)
+		ctx.StopTimer()
+		haltChildren()
+		ctx.NotifyStopped()
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case msg := <-ctx.SystemMessages():
+				handleSystem(msg)
+				continue
+			case <-done:
+				drain()
+				return
+			default:
+			}
+
+			var ready <-chan struct{}
+			if !stopping && !suspended {
+				if msg, ok := ctx.NextMessage(); ok {
+					handle(msg)
+					continue
 				}
+				ready = ctx.Mailbox().Ready()
+			}
+
+			select {
+			case msg := <-ctx.SystemMessages():
+				handleSystem(msg)
+			case <-ready:
+			case <-done:
+				drain()
+				return
 			}
 		}
 	}()
 	ctx.Debug("PostStart '%v'", ctx.Self().Name())
-	if ctx.Clove().PostStart != nil {
-		ctx.Clove().PostStart(ctx)
+	if err := callLifecycle(ctx.Clove().PostStart, ctx); err != nil {
+		ctx.Error("PostStart of '%v' failed: %v", ctx.Self().Path(), err)
+		<-ctx.Self().tellSystem(Stop{}).Result()
+		return err
 	}
-}
\ No newline at end of file
+	ctx.System().EventStream().Publish(CloveStarted{Ref: ctx.Self()})
+	return nil
+}
@@ -0,0 +1,197 @@
+package golik
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestSystem(t *testing.T) Golik {
+	sys, err := NewSystem("test")
+	if err != nil {
+		t.Fatalf("NewSystem failed: %v", err)
+	}
+	return sys
+}
+
+func terminate(t *testing.T, sys Golik) {
+	sys.Terminate()
+	select {
+	case <-sys.Terminated():
+	case <-time.After(5 * time.Second):
+		t.Error("System did not terminate")
+	}
+}
+
+func expectEvents(t *testing.T, events <-chan string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		select {
+		case got := <-events:
+			if got != w {
+				t.Fatalf("Got event %q, want %q", got, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Missing event %q", w)
+		}
+	}
+}
+
+// lifecycleClove reports its lifecycle and the lifecycle of its child to
+// events, the child is started in PostStart.
+func lifecycleClove(name string, events chan<- string) *Clove {
+	record := func(event string) LifecycleFunc {
+		return func(ctx CloveContext) error {
+			events <- event
+			return nil
+		}
+	}
+
+	return &Clove{
+		Name:     name,
+		PreStart: record("PreStart"),
+		PostStart: func(ctx CloveContext) error {
+			_, err := ctx.Run(&Clove{
+				Name:     "child",
+				PostStop: record("child PostStop"),
+				Receive: func(ctx CloveContext) func(msg Message) {
+					return func(msg Message) {
+						msg.Reply(msg.Payload)
+					}
+				},
+			})
+			events <- "PostStart"
+			return err
+		},
+		PreStop:  record("PreStop"),
+		PostStop: record("PostStop"),
+		Receive: func(ctx CloveContext) func(msg Message) {
+			events <- "Receive"
+			return func(msg Message) {
+				msg.Reply(msg.Payload)
+			}
+		},
+	}
+}
+
+func TestRestartTearsDownAndStartsAgain(t *testing.T) {
+	sys := newTestSystem(t)
+	defer terminate(t, sys)
+
+	events := make(chan string, 32)
+	ref, err := sys.Run(lifecycleClove("restarted", events))
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	expectEvents(t, events, "PreStart", "Receive", "PostStart")
+
+	ref.Tell(Kill{})
+	expectEvents(t, events, "PreStop", "child PostStop", "PostStop", "PreStart", "Receive", "PostStart")
+
+	child, ok := sys.At("/usr/restarted/child")
+	if !ok {
+		t.Fatal("Child was not started again")
+	}
+	if result := <-child.Request("ping"); result != "ping" {
+		t.Errorf("Child replied %v, want ping", result)
+	}
+	if result := <-ref.Request("pong"); result != "pong" {
+		t.Errorf("Restarted clove replied %v, want pong", result)
+	}
+}
+
+func TestGracefulStopHandlesQueuedMessages(t *testing.T) {
+	sys := newTestSystem(t)
+	defer terminate(t, sys)
+
+	events := make(chan string, 32)
+	handled := make(chan int, 10)
+	ref, err := sys.Run(&Clove{
+		Name:    "stopped",
+		PreStop: func(ctx CloveContext) error { events <- "PreStop"; return nil },
+		Receive: func(ctx CloveContext) func(msg Message) {
+			return func(msg Message) {
+				time.Sleep(time.Millisecond)
+				handled <- msg.Payload.(int)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		ref.Tell(i)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := GracefulStop(ctx, ref); err != nil {
+		t.Fatalf("GracefulStop failed: %v", err)
+	}
+
+	if len(handled) != 10 {
+		t.Errorf("Handled %v messages before the stop, want 10", len(handled))
+	}
+	expectEvents(t, events, "PreStop")
+	if _, ok := sys.At("/usr/stopped"); ok {
+		t.Error("Stopped clove is still reachable")
+	}
+}
+
+func TestFailingPreStartFailsRun(t *testing.T) {
+	sys := newTestSystem(t)
+	defer terminate(t, sys)
+
+	failure := errors.New("Test failure")
+	_, err := sys.Run(&Clove{
+		Name:     "failing",
+		PreStart: func(ctx CloveContext) error { return failure },
+		Receive: func(ctx CloveContext) func(msg Message) {
+			return func(msg Message) {}
+		},
+	})
+	if err != failure {
+		t.Errorf("Run returned %v, want %v", err, failure)
+	}
+}
+
+func TestSupervisorStopsChildWhenRetriesExceeded(t *testing.T) {
+	sys := newTestSystem(t)
+	defer terminate(t, sys)
+
+	events := make(chan string, 32)
+	_, err := sys.Run(&Clove{
+		Name:               "supervisor",
+		SupervisorStrategy: OneForOneStrategy(1, time.Minute, nil),
+		PostStart: func(ctx CloveContext) error {
+			child, err := ctx.Run(&Clove{
+				Name:     "child",
+				PreStart: func(ctx CloveContext) error { events <- "child PreStart"; return nil },
+				PostStop: func(ctx CloveContext) error { events <- "child PostStop"; return nil },
+				Receive: func(ctx CloveContext) func(msg Message) {
+					return func(msg Message) {}
+				},
+			})
+			if err != nil {
+				return err
+			}
+			child.Tell(Kill{})
+			child.Tell(Kill{})
+			return nil
+		},
+		Receive: func(ctx CloveContext) func(msg Message) {
+			return func(msg Message) {}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	expectEvents(t, events, "child PreStart", "child PostStop", "child PreStart", "child PostStop")
+	select {
+	case event := <-events:
+		t.Errorf("Unexpected event %q after the child was stopped", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
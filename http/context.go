@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"io"
 	ht "net/http"
 
@@ -19,6 +20,10 @@ func (ctx *httpRouteContext) System() golik.Golik {
 	return ctx.system
 }
 
+func (ctx *httpRouteContext) Context() context.Context {
+	return ctx.request.Context()
+}
+
 func (ctx *httpRouteContext) Header() golik.Values {
 	header := make(map[string]string)
 	uheader := ctx.request.Header
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	ht "net/http"
 	"time"
 
@@ -40,19 +41,29 @@ func NewHttp(name string, system golik.Golik) (*HttpService, error) {
 }
 
 func (hs *HttpService) CreateInstance(system golik.Golik) *golik.Clove {
+	system.Shutdown().AddTask(golik.PhaseStopAcceptingHTTP, "stop-http-"+hs.name, func(c context.Context) error {
+		if ref, ok := system.At("/srv/" + hs.name); ok {
+			return golik.GracefulStop(c, ref)
+		}
+		return nil
+	})
+
 	return &golik.Clove{
 		Name: hs.name,
 		Receive: func(ctx golik.CloveContext) func(msg golik.Message) {
 
 			return func(msg golik.Message) {
-
+				if _, ok := msg.Payload.(golik.ConfigChanged); ok {
+					hs.reload(ctx)
+				}
 			}
 		},
-		PreStart: func(ctx golik.CloveContext) {
-			hs.run(ctx)
+		ConfigKeys: []string{"http"},
+		PreStart: func(ctx golik.CloveContext) error {
+			return hs.run(ctx)
 		},
-		PreStop: func(ctx golik.CloveContext) {
-			hs.shutdown(ctx)
+		PreStop: func(ctx golik.CloveContext) error {
+			return hs.shutdown(ctx)
 		},
 	}
 }
@@ -61,7 +72,7 @@ func (hs *HttpService) System() golik.Golik {
 	return hs.system
 }
 
-func (hs *HttpService) run(ctx golik.CloveContext) {
+func (hs *HttpService) run(ctx golik.CloveContext) error {
 	//hs.system = ctx.System()
 	hs.log = ctx.Logger()
 	settings := newHTTPSettings(hs.name)
@@ -74,13 +85,33 @@ func (hs *HttpService) run(ctx golik.CloveContext) {
 		IdleTimeout:  settings.IdleTimeout,
 	}
 
+	// bind before serving, so an address in use fails the start of the service
+	listener, err := net.Listen("tcp", hs.server.Addr)
+	if err != nil {
+		return err
+	}
+
 	go func() {
-		if err := hs.server.ListenAndServe(); err != nil && err != ht.ErrServerClosed {
+		if err := hs.server.Serve(listener); err != nil && err != ht.ErrServerClosed {
 			ctx.Error("Error in http-service execution ...", err)
 		}
 	}()
 
 	ctx.Info("Http-Server '%v' is listening on %v", hs.name, settings.Addr())
+	return nil
+}
+
+func (hs *HttpService) reload(ctx golik.CloveContext) {
+	settings := newHTTPSettings(hs.name)
+	if *settings == *hs.settings {
+		return
+	}
+
+	ctx.Info("Settings of Http-Server '%v' changed, rebind ...", hs.name)
+	hs.shutdown(ctx)
+	if err := hs.run(ctx); err != nil {
+		ctx.Error("Could not rebind Http-Server '%v': %v", hs.name, err)
+	}
 }
 
 func (hs *HttpService) shutdown(ctx golik.CloveContext) error {
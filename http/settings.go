@@ -4,6 +4,7 @@ import (
 	"time"
 	"fmt"
 
+	"github.com/ioswarm/golik"
 	"github.com/spf13/viper"
 )
 
@@ -32,7 +33,17 @@ func newBaseHTTPSettings() *httpSettings {
 	}
 }
 
+// newHTTPSettings reads the settings of the service name, a reload of the
+// config file does not interfere.
 func newHTTPSettings(name string) *httpSettings {
+	var settings *httpSettings
+	golik.ReadSettings(func() {
+		settings = readHTTPSettings(name)
+	})
+	return settings
+}
+
+func readHTTPSettings(name string) *httpSettings {
 	bs := newBaseHTTPSettings()
 
 	getPath := func(segment string) string {
@@ -74,22 +74,16 @@ func initLogging() {
 	if !loggingInit {
 		initSettings()
 
-		switch viper.GetString("golik.log.level") {
-		case "DEBUG": 
-			logrus.SetLevel(logrus.DebugLevel)
-		case "WARN":
-			logrus.SetLevel(logrus.WarnLevel)
-		case "ERROR":
-			logrus.SetLevel(logrus.ErrorLevel)
-		case "FATAL":
-			logrus.SetLevel(logrus.FatalLevel)
-		case "PANIC":
-			logrus.SetLevel(logrus.PanicLevel)
-		default:
-			logrus.SetLevel(logrus.InfoLevel)
-		}
+		var levelName, formatter string
+		ReadSettings(func() {
+			levelName = viper.GetString("golik.log.level")
+			formatter = viper.GetString("golik.log.formatter")
+		})
+
+		level, _ := parseLogLevel(levelName)
+		logrus.SetLevel(level)
 	
-		switch viper.GetString("golik.log.formatter") {
+		switch formatter {
 		case "json":
 			logrus.SetFormatter(&logrus.JSONFormatter{
 				TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
@@ -0,0 +1,209 @@
+package golik
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type Mailbox interface {
+	Push(msg Message) error
+	Pop() (Message, bool)
+	Ready() <-chan struct{}
+	Len() int
+	Cap() int
+	Close()
+}
+
+// MailboxFactory creates the mailbox of a clove, it is called on every run.
+type MailboxFactory func() Mailbox
+
+type Prioritized interface {
+	Priority() int
+}
+
+type MailboxError struct {
+	Dropped Message
+	Reason  string
+}
+
+func (err *MailboxError) Error() string {
+	return err.Reason
+}
+
+type OverflowPolicy uint8
+
+const (
+	BlockOnOverflow OverflowPolicy = iota
+	DropNewest
+	DropOldest
+)
+
+func UnboundedMailbox() Mailbox {
+	return newQueueMailbox(0, BlockOnOverflow, 0, nil)
+}
+
+func DropNewestMailbox(capacity int) Mailbox {
+	return newQueueMailbox(capacity, DropNewest, 0, nil)
+}
+
+func DropOldestMailbox(capacity int) Mailbox {
+	return newQueueMailbox(capacity, DropOldest, 0, nil)
+}
+
+// BlockingMailbox blocks the sender while the mailbox is full. A timeout of 0
+// blocks until there is space, otherwise the message is dropped after timeout.
+func BlockingMailbox(capacity int, timeout time.Duration) Mailbox {
+	return newQueueMailbox(capacity, BlockOnOverflow, timeout, nil)
+}
+
+// PriorityMailbox orders messages with less; if less is nil, payloads
+// implementing Prioritized are ordered by descending Priority().
+func PriorityMailbox(less func(a, b Message) bool) Mailbox {
+	if less == nil {
+		less = PayloadPriority
+	}
+	return newQueueMailbox(0, BlockOnOverflow, 0, less)
+}
+
+func PayloadPriority(a, b Message) bool {
+	priority := func(m Message) int {
+		if p, ok := m.Payload.(Prioritized); ok {
+			return p.Priority()
+		}
+		return 0
+	}
+	return priority(a) > priority(b)
+}
+
+func newQueueMailbox(capacity int, policy OverflowPolicy, timeout time.Duration, less func(a, b Message) bool) *queueMailbox {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &queueMailbox{
+		queue:    make([]Message, 0),
+		capacity: capacity,
+		policy:   policy,
+		timeout:  timeout,
+		less:     less,
+		ready:    make(chan struct{}, 1),
+		space:    make(chan struct{}, 1),
+	}
+}
+
+type queueMailbox struct {
+	mutex    sync.Mutex
+	queue    []Message
+	capacity int
+	policy   OverflowPolicy
+	timeout  time.Duration
+	less     func(a, b Message) bool
+	ready    chan struct{}
+	space    chan struct{}
+	closed   bool
+}
+
+func notify(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
+
+func (mb *queueMailbox) full() bool {
+	return mb.capacity > 0 && len(mb.queue) >= mb.capacity
+}
+
+func (mb *queueMailbox) insert(msg Message) {
+	if mb.less == nil {
+		mb.queue = append(mb.queue, msg)
+	} else {
+		i := sort.Search(len(mb.queue), func(i int) bool {
+			return mb.less(msg, mb.queue[i])
+		})
+		mb.queue = append(mb.queue, Message{})
+		copy(mb.queue[i+1:], mb.queue[i:])
+		mb.queue[i] = msg
+	}
+	if !mb.full() {
+		notify(mb.space)
+	}
+	notify(mb.ready)
+}
+
+func (mb *queueMailbox) Push(msg Message) error {
+	var deadline <-chan time.Time
+	for {
+		mb.mutex.Lock()
+		if mb.closed {
+			notify(mb.space)
+			mb.mutex.Unlock()
+			return &MailboxError{Dropped: msg, Reason: "mailbox is closed"}
+		}
+		if !mb.full() {
+			mb.insert(msg)
+			mb.mutex.Unlock()
+			return nil
+		}
+
+		switch mb.policy {
+		case DropNewest:
+			mb.mutex.Unlock()
+			return &MailboxError{Dropped: msg, Reason: "mailbox is full, dropped newest"}
+		case DropOldest:
+			oldest := mb.queue[0]
+			mb.queue = mb.queue[1:]
+			mb.insert(msg)
+			mb.mutex.Unlock()
+			return &MailboxError{Dropped: oldest, Reason: "mailbox is full, dropped oldest"}
+		}
+		mb.mutex.Unlock()
+
+		if mb.timeout > 0 && deadline == nil {
+			deadline = time.After(mb.timeout)
+		}
+		select {
+		case <-mb.space:
+		case <-deadline:
+			return &MailboxError{Dropped: msg, Reason: "mailbox is full, timeout exceeded"}
+		}
+	}
+}
+
+func (mb *queueMailbox) Pop() (Message, bool) {
+	mb.mutex.Lock()
+	defer mb.mutex.Unlock()
+
+	if len(mb.queue) == 0 {
+		return Message{}, false
+	}
+	msg := mb.queue[0]
+	mb.queue[0] = Message{}
+	mb.queue = mb.queue[1:]
+	notify(mb.space)
+	if len(mb.queue) > 0 {
+		notify(mb.ready)
+	}
+	return msg, true
+}
+
+func (mb *queueMailbox) Ready() <-chan struct{} {
+	return mb.ready
+}
+
+func (mb *queueMailbox) Len() int {
+	mb.mutex.Lock()
+	defer mb.mutex.Unlock()
+	return len(mb.queue)
+}
+
+func (mb *queueMailbox) Cap() int {
+	return mb.capacity
+}
+
+func (mb *queueMailbox) Close() {
+	mb.mutex.Lock()
+	defer mb.mutex.Unlock()
+	mb.closed = true
+	notify(mb.space)
+}
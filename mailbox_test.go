@@ -0,0 +1,142 @@
+package golik
+
+import (
+	"testing"
+	"time"
+)
+
+type prioritized int
+
+func (p prioritized) Priority() int {
+	return int(p)
+}
+
+func popAll(mb Mailbox) []interface{} {
+	payloads := make([]interface{}, 0)
+	for msg, ok := mb.Pop(); ok; msg, ok = mb.Pop() {
+		payloads = append(payloads, msg.Payload)
+	}
+	return payloads
+}
+
+func equalPayloads(a []interface{}, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDropNewestMailbox(t *testing.T) {
+	mb := DropNewestMailbox(2)
+	for i := 1; i <= 2; i++ {
+		if err := mb.Push(NewMessage(nil, i)); err != nil {
+			t.Fatalf("Push(%v) failed: %v", i, err)
+		}
+	}
+
+	err := mb.Push(NewMessage(nil, 3))
+	merr, ok := err.(*MailboxError)
+	if !ok {
+		t.Fatalf("Push to full mailbox returned %v, want *MailboxError", err)
+	}
+	if merr.Dropped.Payload != 3 {
+		t.Errorf("Dropped %v, want 3", merr.Dropped.Payload)
+	}
+	if got := popAll(mb); !equalPayloads(got, []interface{}{1, 2}) {
+		t.Errorf("Mailbox holds %v, want [1 2]", got)
+	}
+}
+
+func TestDropOldestMailbox(t *testing.T) {
+	mb := DropOldestMailbox(2)
+	for i := 1; i <= 2; i++ {
+		if err := mb.Push(NewMessage(nil, i)); err != nil {
+			t.Fatalf("Push(%v) failed: %v", i, err)
+		}
+	}
+
+	err := mb.Push(NewMessage(nil, 3))
+	merr, ok := err.(*MailboxError)
+	if !ok {
+		t.Fatalf("Push to full mailbox returned %v, want *MailboxError", err)
+	}
+	if merr.Dropped.Payload != 1 {
+		t.Errorf("Dropped %v, want 1", merr.Dropped.Payload)
+	}
+	if got := popAll(mb); !equalPayloads(got, []interface{}{2, 3}) {
+		t.Errorf("Mailbox holds %v, want [2 3]", got)
+	}
+}
+
+func TestBlockingMailboxTimeout(t *testing.T) {
+	mb := BlockingMailbox(1, 20*time.Millisecond)
+	if err := mb.Push(NewMessage(nil, 1)); err != nil {
+		t.Fatalf("Push(1) failed: %v", err)
+	}
+
+	start := time.Now()
+	err := mb.Push(NewMessage(nil, 2))
+	if _, ok := err.(*MailboxError); !ok {
+		t.Fatalf("Push to full mailbox returned %v, want *MailboxError", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Push returned after %v, want to block for the timeout", elapsed)
+	}
+}
+
+func TestBlockingMailboxWaitsForSpace(t *testing.T) {
+	mb := BlockingMailbox(1, time.Second)
+	if err := mb.Push(NewMessage(nil, 1)); err != nil {
+		t.Fatalf("Push(1) failed: %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		mb.Pop()
+	}()
+	if err := mb.Push(NewMessage(nil, 2)); err != nil {
+		t.Fatalf("Push(2) failed: %v", err)
+	}
+	if got := popAll(mb); !equalPayloads(got, []interface{}{2}) {
+		t.Errorf("Mailbox holds %v, want [2]", got)
+	}
+}
+
+func TestClosedMailbox(t *testing.T) {
+	mb := BlockingMailbox(1, 0)
+	mb.Close()
+	if err := mb.Push(NewMessage(nil, 1)); err == nil {
+		t.Error("Push to closed mailbox succeeded")
+	}
+}
+
+func TestPriorityMailbox(t *testing.T) {
+	mb := PriorityMailbox(nil)
+	for _, p := range []interface{}{prioritized(1), "plain", prioritized(5), prioritized(1), prioritized(3)} {
+		mb.Push(NewMessage(nil, p))
+	}
+
+	want := []interface{}{prioritized(5), prioritized(3), prioritized(1), prioritized(1), "plain"}
+	if got := popAll(mb); !equalPayloads(got, want) {
+		t.Errorf("Mailbox pops %v, want %v", got, want)
+	}
+}
+
+func TestPriorityMailboxKeepsOrderOfEqualPriorities(t *testing.T) {
+	mb := PriorityMailbox(func(a, b Message) bool {
+		return a.Payload.(int)/10 > b.Payload.(int)/10
+	})
+	for _, p := range []int{11, 21, 12, 22, 13} {
+		mb.Push(NewMessage(nil, p))
+	}
+
+	want := []interface{}{21, 22, 11, 12, 13}
+	if got := popAll(mb); !equalPayloads(got, want) {
+		t.Errorf("Mailbox pops %v, want %v", got, want)
+	}
+}
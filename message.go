@@ -1,10 +1,91 @@
 package golik
 
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type Envelope struct {
+	ID            string
+	CorrelationID string
+	CausationID   string
+	Timestamp     time.Time
+	Headers       Values
+}
+
 type Message struct {
 	Payload interface{}
+	envelope Envelope
 	sender *CloveRef
-	reply chan interface{}
-	// TODO define message-ids for monitoring and tracking
+	recipient *CloveRef
+	reply *replyChannel
+}
+
+type replyChannel struct {
+	once    sync.Once
+	results chan interface{}
+}
+
+func (m Message) Envelope() Envelope {
+	env := m.envelope
+	env.Headers = make(Values, len(m.envelope.Headers))
+	for k, v := range m.envelope.Headers {
+		env.Headers.Add(k, v)
+	}
+	return env
+}
+
+func (m Message) ID() string {
+	return m.envelope.ID
+}
+
+func (m Message) CorrelationID() string {
+	return m.envelope.CorrelationID
+}
+
+func (m Message) CausationID() string {
+	return m.envelope.CausationID
+}
+
+func (m Message) Timestamp() time.Time {
+	return m.envelope.Timestamp
+}
+
+func (m Message) Header(key string) string {
+	return m.envelope.Headers.Get(key)
+}
+
+func (m Message) WithHeader(key string, value string) Message {
+	headers := make(Values, len(m.envelope.Headers)+1)
+	for k, v := range m.envelope.Headers {
+		headers.Add(k, v)
+	}
+	headers.Add(key, value)
+	m.envelope.Headers = headers
+	return m
+}
+
+func (m Message) WithCorrelationID(id string) Message {
+	m.envelope.CorrelationID = id
+	return m
+}
+
+func (m Message) causedBy(cause Message) Message {
+	m.envelope.CorrelationID = cause.envelope.CorrelationID
+	m.envelope.CausationID = cause.envelope.ID
+	if len(cause.envelope.Headers) > 0 {
+		headers := make(Values, len(cause.envelope.Headers)+len(m.envelope.Headers))
+		for k, v := range cause.envelope.Headers {
+			headers.Add(k, v)
+		}
+		for k, v := range m.envelope.Headers {
+			headers.Add(k, v)
+		}
+		m.envelope.Headers = headers
+	}
+	return m
 }
 
 func (m Message) Sender() (*CloveRef, bool) {
@@ -12,17 +93,63 @@ func (m Message) Sender() (*CloveRef, bool) {
 }
 
 func (m Message) Reply(result interface{}) {
-	m.reply <- result
-	close(m.reply)
+	if m.reply != nil {
+		m.reply.once.Do(func() {
+			m.reply.results <- result
+			close(m.reply.results)
+		})
+		return
+	}
+	if m.sender != nil && result != nil {
+		m.sender.deliver(NewMessage(m.recipient, result).causedBy(m))
+	}
 }
 
 func (m Message) Result() <- chan interface{} {
-	return m.reply
+	if m.reply == nil {
+		return nil
+	}
+	return m.reply.results
+}
+
+func (m Message) awaited() bool {
+	return m.reply != nil
+}
+
+func newEnvelope() Envelope {
+	id := newMessageID()
+	return Envelope{
+		ID:            id,
+		CorrelationID: id,
+		Timestamp:     time.Now(),
+	}
+}
+
+func newMessageID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
 }
 
 func NewMessage(sender *CloveRef, payload interface{}) Message {
 	return Message{
 		Payload: payload,
-		reply: make(chan interface{}, 1),
+		envelope: newEnvelope(),
+		sender: sender,
+	}
+}
+
+func NewRequest(sender *CloveRef, payload interface{}) Message {
+	return Message{
+		Payload: payload,
+		envelope: newEnvelope(),
+		sender: sender,
+		reply: &replyChannel{
+			results: make(chan interface{}, 1),
+		},
 	}
-}
\ No newline at end of file
+}
@@ -7,7 +7,7 @@ import (
 )
 
 type MinionHandler interface {
-	CallLifeCycle(string, CloveContext)
+	CallLifeCycle(string, CloveContext) error
 	HandleReceive(ctx CloveContext) func(Message)
 }
 
@@ -57,17 +57,17 @@ func Minion(obj interface{}, conf MinionConfig) *Clove {
 	return &Clove{
 		Name: name,
 		Receive: mHandler.HandleReceive,
-		PreStart: func(ctx CloveContext) {
-			mHandler.CallLifeCycle("PreStart", ctx)
+		PreStart: func(ctx CloveContext) error {
+			return mHandler.CallLifeCycle("PreStart", ctx)
 		},
-		PostStart: func(ctx CloveContext) {
-			mHandler.CallLifeCycle("PostStart", ctx)
+		PostStart: func(ctx CloveContext) error {
+			return mHandler.CallLifeCycle("PostStart", ctx)
 		},
-		PreStop: func(ctx CloveContext) {
-			mHandler.CallLifeCycle("PreStop", ctx)
+		PreStop: func(ctx CloveContext) error {
+			return mHandler.CallLifeCycle("PreStop", ctx)
 		},
-		PostStop: func(ctx CloveContext) {
-			mHandler.CallLifeCycle("PostStop", ctx)
+		PostStop: func(ctx CloveContext) error {
+			return mHandler.CallLifeCycle("PostStop", ctx)
 		},
 		Async: conf.Async,
 		BufferSize: conf.BufferSize,
@@ -87,8 +87,8 @@ type minionHandler struct {
 	mutex sync.Mutex
 }
 
-func (mh *minionHandler) CallLifeCycle(methodName string, ctx CloveContext) {
-	CallLifeCycle(mh.minion, methodName, ctx)
+func (mh *minionHandler) CallLifeCycle(methodName string, ctx CloveContext) error {
+	return CallLifeCycle(mh.minion, methodName, ctx)
 }
 
 func (mh *minionHandler) HandleReceive(ctx CloveContext) func(Message) {
@@ -102,7 +102,7 @@ func (mh *minionHandler) HandleReceive(ctx CloveContext) func(Message) {
 			defer mh.mutex.Unlock()
 		}
 
-		if result, ok := CallMethod(mh.minion, ctx, msg.Payload); ok {
+		if result, ok := CallMethod(mh.minion, WithMessage(ctx, msg), msg.Payload); ok {
 			msg.Reply(result)
 		}
 
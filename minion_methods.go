@@ -7,22 +7,31 @@ import (
 	"github.com/ioswarm/golik/utils"
 )
 
-func CallLifeCycle(obj interface{}, methodName string, ctx CloveContext) {
+// CallLifeCycle calls the lifecycle method methodName of obj, if it exists.
+// The method may take the CloveContext and may return an error.
+func CallLifeCycle(obj interface{}, methodName string, ctx CloveContext) error {
 	if obj != nil {
 		objValue := utils.ToPtrValue(reflect.ValueOf(obj))
 		if methodValue := objValue.MethodByName(methodName); methodValue.IsValid() {
 			methodType := methodValue.Type()
+			var result []reflect.Value
 			switch methodType.NumIn() {
 			case 0:
-				methodValue.Call(nil)
+				result = methodValue.Call(nil)
 			case 1:
 				ctype := reflect.TypeOf(ctx)
 				if ctype.Implements(methodType.In(0)) {
-					methodValue.Call([]reflect.Value{reflect.ValueOf(ctx)})
+					result = methodValue.Call([]reflect.Value{reflect.ValueOf(ctx)})
+				}
+			}
+			if len(result) == 1 && utils.IsErrorType(methodType.Out(0)) {
+				if err, ok := result[0].Interface().(error); ok {
+					return err
 				}
 			}
 		}
 	}
+	return nil
 }
 
 func CallMethod(obj interface{}, ctx CloveContext, input interface{}) (interface{}, bool) {
@@ -0,0 +1,69 @@
+package golik
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/spf13/viper"
+)
+
+var nameAdjectives = []string{
+	"amber", "brave", "calm", "clever", "crisp", "eager", "fancy", "gentle",
+	"happy", "hidden", "jolly", "keen", "lively", "lucky", "mellow", "misty",
+	"nimble", "noble", "proud", "quiet", "rapid", "shiny", "silent", "sleepy",
+	"steady", "sunny", "swift", "tidy", "vivid", "wild", "witty", "zesty",
+}
+
+var nameNouns = []string{
+	"badger", "beacon", "bison", "canyon", "comet", "coral", "falcon", "fern",
+	"glacier", "harbor", "heron", "island", "lagoon", "lynx", "maple", "meadow",
+	"otter", "owl", "panda", "pebble", "pine", "quail", "raven", "river",
+	"robin", "sparrow", "spruce", "summit", "tiger", "tulip", "walrus", "willow",
+}
+
+// counterName encodes n as letters: 0 -> a, 25 -> z, 26 -> ba, ...
+func counterName(n uint64) string {
+	var sb []byte
+	for {
+		sb = append([]byte{byte('a' + n%26)}, sb...)
+		n /= 26
+		if n == 0 {
+			break
+		}
+	}
+	return string(sb)
+}
+
+func (c *cloveRunnable) nextName() string {
+	var generator, prefix string
+	ReadSettings(func() {
+		generator = viper.GetString("golik.names.generator")
+		prefix = viper.GetString("golik.names.prefix")
+	})
+
+	switch strings.ToLower(generator) {
+	case "adjective-noun":
+		return nameAdjectives[rand.Intn(len(nameAdjectives))] + "-" + nameNouns[rand.Intn(len(nameNouns))]
+	default:
+		return prefix + counterName(atomic.AddUint64(&c.nameCounter, 1)-1)
+	}
+}
+
+func (c *cloveRunnable) generateName() string {
+	for i := 0; ; i++ {
+		name := c.nextName()
+		if i >= 10 {
+			name += "-" + strconv.FormatUint(atomic.AddUint64(&c.nameCounter, 1), 10)
+		}
+		if _, exists := c.child(name); !exists {
+			return name
+		}
+	}
+}
+
+func init() {
+	viper.SetDefault("golik.names.generator", "counter")
+	viper.SetDefault("golik.names.prefix", "$")
+}
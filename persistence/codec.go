@@ -0,0 +1,71 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	typesMutex sync.RWMutex
+	types      = make(map[string]reflect.Type)
+)
+
+// RegisterType makes the type of every sample known to the file based
+// journal and snapshot store, so persisted values can be decoded on replay.
+func RegisterType(samples ...interface{}) {
+	typesMutex.Lock()
+	defer typesMutex.Unlock()
+	for _, sample := range samples {
+		if sample == nil {
+			continue
+		}
+		t := reflect.TypeOf(sample)
+		types[t.String()] = t
+	}
+}
+
+func encodeValue(value interface{}) (string, json.RawMessage, error) {
+	if value == nil {
+		return "", nil, errors.New("Cannot encode nil value")
+	}
+	name := reflect.TypeOf(value).String()
+
+	typesMutex.RLock()
+	_, ok := types[name]
+	typesMutex.RUnlock()
+	if !ok {
+		return "", nil, fmt.Errorf("Type %v is not registered", name)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, data, nil
+}
+
+func decodeValue(name string, data json.RawMessage) (interface{}, error) {
+	typesMutex.RLock()
+	t, ok := types[name]
+	typesMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Type %v is not registered", name)
+	}
+
+	if t.Kind() == reflect.Ptr {
+		value := reflect.New(t.Elem())
+		if err := json.Unmarshal(data, value.Interface()); err != nil {
+			return nil, err
+		}
+		return value.Interface(), nil
+	}
+
+	value := reflect.New(t)
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return nil, err
+	}
+	return value.Elem().Interface(), nil
+}
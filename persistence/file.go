@@ -0,0 +1,276 @@
+package persistence
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type fileRecord struct {
+	SequenceNr uint64          `json:"seq"`
+	Type       string          `json:"type"`
+	Timestamp  time.Time       `json:"ts"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewFileJournal stores the events of every persistence id in an append-only
+// log below dir, one JSON record per line. Event types must be registered
+// with RegisterType.
+func NewFileJournal(dir string) (Journal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &fileJournal{
+		dir:     dir,
+		highest: make(map[string]uint64),
+	}, nil
+}
+
+type fileJournal struct {
+	mutex   sync.Mutex
+	dir     string
+	highest map[string]uint64
+}
+
+func (j *fileJournal) filename(persistenceID string) string {
+	return filepath.Join(j.dir, url.PathEscape(persistenceID)+".journal")
+}
+
+// scan reads all complete records of a log. A torn record at the end of the
+// file, left by an interrupted write, is cut off.
+func (j *fileJournal) scan(persistenceID string, f func(record fileRecord) error) error {
+	file, err := os.OpenFile(j.filename(persistenceID), os.O_RDWR, 0644)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) > 0 {
+				return file.Truncate(offset)
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var record fileRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("Corrupt record in journal of '%v' at offset %v: %v", persistenceID, offset, err)
+		}
+		offset += int64(len(line))
+		if err := f(record); err != nil {
+			return err
+		}
+	}
+}
+
+func (j *fileJournal) highestSequenceNr(persistenceID string) (uint64, error) {
+	if highest, ok := j.highest[persistenceID]; ok {
+		return highest, nil
+	}
+	var highest uint64
+	err := j.scan(persistenceID, func(record fileRecord) error {
+		highest = record.SequenceNr
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	j.highest[persistenceID] = highest
+	return highest, nil
+}
+
+func (j *fileJournal) Write(events []PersistentRepr) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	buffers := make(map[string]*bytes.Buffer)
+	next := make(map[string]uint64)
+	for _, repr := range events {
+		expected, ok := next[repr.PersistenceID]
+		if !ok {
+			highest, err := j.highestSequenceNr(repr.PersistenceID)
+			if err != nil {
+				return err
+			}
+			expected = highest + 1
+			buffers[repr.PersistenceID] = &bytes.Buffer{}
+		}
+		if repr.SequenceNr != expected {
+			return fmt.Errorf("Unexpected sequence number %v for '%v', expected %v", repr.SequenceNr, repr.PersistenceID, expected)
+		}
+		next[repr.PersistenceID] = expected + 1
+
+		name, data, err := encodeValue(repr.Event)
+		if err != nil {
+			return err
+		}
+		line, err := json.Marshal(fileRecord{
+			SequenceNr: repr.SequenceNr,
+			Type:       name,
+			Timestamp:  repr.Timestamp,
+			Data:       data,
+		})
+		if err != nil {
+			return err
+		}
+		buffers[repr.PersistenceID].Write(append(line, '\n'))
+	}
+
+	for id, buffer := range buffers {
+		if err := j.append(id, buffer.Bytes()); err != nil {
+			return err
+		}
+		j.highest[id] = next[id] - 1
+	}
+	return nil
+}
+
+func (j *fileJournal) append(persistenceID string, data []byte) error {
+	file, err := os.OpenFile(j.filename(persistenceID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		delete(j.highest, persistenceID)
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		delete(j.highest, persistenceID)
+		return err
+	}
+	return file.Close()
+}
+
+func (j *fileJournal) Replay(persistenceID string, fromSequenceNr uint64, f func(repr PersistentRepr) error) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.scan(persistenceID, func(record fileRecord) error {
+		if record.SequenceNr < fromSequenceNr {
+			return nil
+		}
+		event, err := decodeValue(record.Type, record.Data)
+		if err != nil {
+			return err
+		}
+		return f(PersistentRepr{
+			PersistenceID: persistenceID,
+			SequenceNr:    record.SequenceNr,
+			Event:         event,
+			Timestamp:     record.Timestamp,
+		})
+	})
+}
+
+func (j *fileJournal) HighestSequenceNr(persistenceID string) (uint64, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.highestSequenceNr(persistenceID)
+}
+
+// NewFileSnapshotStore keeps the latest snapshot of every persistence id in
+// its own file below dir. State types must be registered with RegisterType.
+func NewFileSnapshotStore(dir string) (SnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &fileSnapshotStore{dir: dir}, nil
+}
+
+type fileSnapshotStore struct {
+	mutex sync.Mutex
+	dir   string
+}
+
+func (s *fileSnapshotStore) filename(persistenceID string) string {
+	return filepath.Join(s.dir, url.PathEscape(persistenceID)+".snapshot")
+}
+
+func (s *fileSnapshotStore) Save(snapshot Snapshot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	name, data, err := encodeValue(snapshot.State)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(fileRecord{
+		SequenceNr: snapshot.SequenceNr,
+		Type:       name,
+		Timestamp:  snapshot.Timestamp,
+		Data:       data,
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(s.dir, ".snapshot-")
+	if err != nil {
+		return err
+	}
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), s.filename(snapshot.PersistenceID))
+}
+
+func (s *fileSnapshotStore) Load(persistenceID string) (Snapshot, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	content, err := ioutil.ReadFile(s.filename(persistenceID))
+	if os.IsNotExist(err) {
+		return Snapshot{}, false, nil
+	} else if err != nil {
+		return Snapshot{}, false, err
+	}
+
+	var record fileRecord
+	if err := json.Unmarshal(content, &record); err != nil {
+		return Snapshot{}, false, err
+	}
+	state, err := decodeValue(record.Type, record.Data)
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	return Snapshot{
+		PersistenceID: persistenceID,
+		SequenceNr:    record.SequenceNr,
+		State:         state,
+		Timestamp:     record.Timestamp,
+	}, true, nil
+}
@@ -0,0 +1,89 @@
+package persistence
+
+import (
+	"fmt"
+	"sync"
+)
+
+func NewMemoryJournal() Journal {
+	return &memoryJournal{
+		events: make(map[string][]PersistentRepr),
+	}
+}
+
+type memoryJournal struct {
+	mutex  sync.RWMutex
+	events map[string][]PersistentRepr
+}
+
+func (j *memoryJournal) Write(events []PersistentRepr) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	next := make(map[string]uint64)
+	for _, repr := range events {
+		expected, ok := next[repr.PersistenceID]
+		if !ok {
+			expected = uint64(len(j.events[repr.PersistenceID])) + 1
+		}
+		if repr.SequenceNr != expected {
+			return fmt.Errorf("Unexpected sequence number %v for '%v', expected %v", repr.SequenceNr, repr.PersistenceID, expected)
+		}
+		next[repr.PersistenceID] = expected + 1
+	}
+
+	for _, repr := range events {
+		j.events[repr.PersistenceID] = append(j.events[repr.PersistenceID], repr)
+	}
+	return nil
+}
+
+func (j *memoryJournal) Replay(persistenceID string, fromSequenceNr uint64, f func(repr PersistentRepr) error) error {
+	j.mutex.RLock()
+	events := j.events[persistenceID]
+	j.mutex.RUnlock()
+
+	for _, repr := range events {
+		if repr.SequenceNr < fromSequenceNr {
+			continue
+		}
+		if err := f(repr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *memoryJournal) HighestSequenceNr(persistenceID string) (uint64, error) {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+	return uint64(len(j.events[persistenceID])), nil
+}
+
+func NewMemorySnapshotStore() SnapshotStore {
+	return &memorySnapshotStore{
+		snapshots: make(map[string]Snapshot),
+	}
+}
+
+type memorySnapshotStore struct {
+	mutex     sync.RWMutex
+	snapshots map[string]Snapshot
+}
+
+func (s *memorySnapshotStore) Save(snapshot Snapshot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if current, ok := s.snapshots[snapshot.PersistenceID]; ok && current.SequenceNr > snapshot.SequenceNr {
+		return nil
+	}
+	s.snapshots[snapshot.PersistenceID] = snapshot
+	return nil
+}
+
+func (s *memorySnapshotStore) Load(persistenceID string) (Snapshot, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	snapshot, ok := s.snapshots[persistenceID]
+	return snapshot, ok, nil
+}
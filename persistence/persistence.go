@@ -0,0 +1,228 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+type EventSourced interface {
+	PersistenceID() string
+	ApplyEvent(repr PersistentRepr)
+}
+
+type Snapshotter interface {
+	SnapshotState() interface{}
+	RestoreSnapshot(snapshot Snapshot)
+}
+
+type PersistentRepr struct {
+	PersistenceID string
+	SequenceNr    uint64
+	Event         interface{}
+	Timestamp     time.Time
+}
+
+type Snapshot struct {
+	PersistenceID string
+	SequenceNr    uint64
+	State         interface{}
+	Timestamp     time.Time
+}
+
+type Journal interface {
+	Write(events []PersistentRepr) error
+	Replay(persistenceID string, fromSequenceNr uint64, f func(repr PersistentRepr) error) error
+	HighestSequenceNr(persistenceID string) (uint64, error)
+}
+
+type SnapshotStore interface {
+	Save(snapshot Snapshot) error
+	Load(persistenceID string) (Snapshot, bool, error)
+}
+
+type Context interface {
+	golik.CloveContext
+	Persist(events ...interface{}) error
+	LastSequenceNr() uint64
+}
+
+type Config struct {
+	Name          string
+	BufferSize    uint32
+	Journal       Journal
+	SnapshotStore SnapshotStore
+	SnapshotEvery uint64
+}
+
+func Persistent(obj EventSourced, conf Config) *golik.Clove {
+	if conf.Journal == nil {
+		conf.Journal = NewMemoryJournal()
+	}
+
+	return golik.Minion(obj, golik.MinionConfig{
+		Name:       conf.Name,
+		BufferSize: conf.BufferSize,
+		Stateful:   true,
+		Handler:    newPersistentHandler(obj, &conf),
+	})
+}
+
+func newPersistentHandler(obj EventSourced, conf *Config) *persistentHandler {
+	return &persistentHandler{
+		minion: obj,
+		conf:   conf,
+	}
+}
+
+type persistentHandler struct {
+	minion        EventSourced
+	conf          *Config
+	mutex         sync.Mutex
+	sequenceNr    uint64
+	sinceSnapshot uint64
+	recovered     bool
+}
+
+func (ph *persistentHandler) CallLifeCycle(methodName string, ctx golik.CloveContext) error {
+	// the minion keeps its state across restarts, so events are only replayed
+	// once; a failed recovery fails PreStart and the clove does not run
+	if methodName == "PreStart" && !ph.recovered {
+		ph.mutex.Lock()
+		err := ph.recover(ctx)
+		ph.recovered = err == nil
+		ph.mutex.Unlock()
+		if err != nil {
+			return fmt.Errorf("Recovery of '%v' failed: %v", ph.minion.PersistenceID(), err)
+		}
+	}
+	return golik.CallLifeCycle(ph.minion, methodName, ctx)
+}
+
+func (ph *persistentHandler) recover(ctx golik.CloveContext) error {
+	id := ph.minion.PersistenceID()
+	ph.sequenceNr = 0
+	ph.sinceSnapshot = 0
+
+	if ph.conf.SnapshotStore != nil {
+		snapshot, ok, err := ph.conf.SnapshotStore.Load(id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			snapshotter, isSnapshotter := ph.minion.(Snapshotter)
+			if !isSnapshotter {
+				return errors.New("Snapshot found but minion does not implement Snapshotter")
+			}
+			snapshotter.RestoreSnapshot(snapshot)
+			ph.sequenceNr = snapshot.SequenceNr
+		}
+	}
+
+	replayed := 0
+	err := ph.conf.Journal.Replay(id, ph.sequenceNr+1, func(repr PersistentRepr) error {
+		ph.minion.ApplyEvent(repr)
+		ph.sequenceNr = repr.SequenceNr
+		ph.sinceSnapshot++
+		replayed++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	highest, err := ph.conf.Journal.HighestSequenceNr(id)
+	if err != nil {
+		return err
+	}
+	if highest > ph.sequenceNr {
+		ph.sequenceNr = highest
+	}
+
+	ctx.Debug("Recovered '%v' with %v events up to sequence %v", id, replayed, ph.sequenceNr)
+	return nil
+}
+
+func (ph *persistentHandler) persist(ctx golik.CloveContext, events []interface{}) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	id := ph.minion.PersistenceID()
+	now := time.Now()
+	reprs := make([]PersistentRepr, len(events))
+	for i, event := range events {
+		reprs[i] = PersistentRepr{
+			PersistenceID: id,
+			SequenceNr:    ph.sequenceNr + uint64(i) + 1,
+			Event:         event,
+			Timestamp:     now,
+		}
+	}
+
+	if err := ph.conf.Journal.Write(reprs); err != nil {
+		return err
+	}
+
+	for _, repr := range reprs {
+		ph.minion.ApplyEvent(repr)
+		ph.sequenceNr = repr.SequenceNr
+		ph.sinceSnapshot++
+	}
+
+	ph.snapshot(ctx)
+	return nil
+}
+
+func (ph *persistentHandler) snapshot(ctx golik.CloveContext) {
+	if ph.conf.SnapshotStore == nil || ph.conf.SnapshotEvery == 0 || ph.sinceSnapshot < ph.conf.SnapshotEvery {
+		return
+	}
+	snapshotter, ok := ph.minion.(Snapshotter)
+	if !ok {
+		return
+	}
+
+	err := ph.conf.SnapshotStore.Save(Snapshot{
+		PersistenceID: ph.minion.PersistenceID(),
+		SequenceNr:    ph.sequenceNr,
+		State:         snapshotter.SnapshotState(),
+		Timestamp:     time.Now(),
+	})
+	if err != nil {
+		ctx.Warn("Could not save snapshot of '%v': %v", ph.minion.PersistenceID(), err)
+		return
+	}
+	ph.sinceSnapshot = 0
+}
+
+func (ph *persistentHandler) HandleReceive(ctx golik.CloveContext) func(golik.Message) {
+	return func(msg golik.Message) {
+		ph.mutex.Lock()
+		defer ph.mutex.Unlock()
+
+		pctx := &persistentContext{
+			CloveContext: golik.WithMessage(ctx, msg),
+			handler:      ph,
+		}
+		if result, ok := golik.CallMethod(ph.minion, pctx, msg.Payload); ok {
+			msg.Reply(result)
+		}
+	}
+}
+
+type persistentContext struct {
+	golik.CloveContext
+	handler *persistentHandler
+}
+
+func (pctx *persistentContext) Persist(events ...interface{}) error {
+	return pctx.handler.persist(pctx.CloveContext, events)
+}
+
+func (pctx *persistentContext) LastSequenceNr() uint64 {
+	return pctx.handler.sequenceNr
+}
@@ -2,7 +2,7 @@ package golik
 
 import (
 	"strconv"
-	"sort"
+	"sync"
 )
 
 func Pool(name string, size int, f func() *Clove) *Clove {
@@ -10,25 +10,119 @@ func Pool(name string, size int, f func() *Clove) *Clove {
 		Name: name,
 		Receive: func(ctx CloveContext) func(msg Message) {
 			return func(msg Message) {
-				children := make([]*CloveRef, len(ctx.Children()))
-				copy(children, ctx.Children())
-				sort.Slice(children, func(i, j int) bool {
-					return children[i].Length() < children[j].Length()
-				})
+				if _, ok := msg.Payload.(ConfigChanged); ok {
+					return
+				}
+
+				var target *CloveRef
+				min := 0
+				for _, child := range ctx.Children() {
+					if l := child.Length(); target == nil || l < min {
+						target, min = child, l
+					}
+				}
 
-				if len(children) > 0 {
-					children[0].Forward(msg)
+				if target != nil {
+					target.Forward(msg)
+				} else {
+					PublishDeadLetter(ctx.System(), msg, ctx.Self(), "pool has no workers")
 				}
 			}
 		},
 		Async: false,
-		PostStart: func(ctx CloveContext) {
+		PostStart: func(ctx CloveContext) error {
 			for i := 0; i < size; i++ {
 				c := f()
 				c.Name = name + "-" + strconv.Itoa(i)
 				ctx.Debug("Create worker: %v", i)
-				ctx.Run(c)
+				if _, err := ctx.Run(c); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type sharedMailbox struct {
+	Mailbox
+}
+
+func (mb sharedMailbox) Close() {
+	// the owning pool closes the underlying mailbox
+}
+
+// BalancingPool lets its workers take messages from one shared mailbox. Every
+// run of the pool gets its own shared mailbox, sized by the bufferSize config
+// of the pool.
+func BalancingPool(name string, size int, f func() *Clove) *Clove {
+	var mutex sync.Mutex
+	mailboxes := make(map[string]Mailbox)
+
+	shared := func(ctx CloveContext) Mailbox {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return mailboxes[ctx.Self().Path()]
+	}
+
+	return &Clove{
+		Name: name,
+		PreStart: func(ctx CloveContext) error {
+			capacity := ctx.Config().GetInt("bufferSize")
+			if capacity <= 0 {
+				capacity = 1000
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			mailboxes[ctx.Self().Path()] = BlockingMailbox(capacity, 0)
+			return nil
+		},
+		Receive: func(ctx CloveContext) func(msg Message) {
+			mailbox := shared(ctx)
+			return func(msg Message) {
+				if _, ok := msg.Payload.(ConfigChanged); ok {
+					return
+				}
+				if len(ctx.Children()) == 0 {
+					PublishDeadLetter(ctx.System(), msg, ctx.Self(), "pool has no workers")
+					return
+				}
+				if err := mailbox.Push(msg); err != nil {
+					PublishDeadLetter(ctx.System(), msg, ctx.Self(), err.Error())
+				}
+			}
+		},
+		Async: false,
+		PostStart: func(ctx CloveContext) error {
+			mailbox := shared(ctx)
+			for i := 0; i < size; i++ {
+				c := f()
+				c.Name = name + "-" + strconv.Itoa(i)
+				c.Mailbox = func() Mailbox {
+					return sharedMailbox{mailbox}
+				}
+				ctx.Debug("Create worker: %v", i)
+				if _, err := ctx.Run(c); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		PostStop: func(ctx CloveContext) error {
+			mutex.Lock()
+			mailbox, ok := mailboxes[ctx.Self().Path()]
+			delete(mailboxes, ctx.Self().Path())
+			mutex.Unlock()
+			if !ok {
+				return nil
+			}
+
+			mailbox.Close()
+			for msg, ok := mailbox.Pop(); ok; msg, ok = mailbox.Pop() {
+				PublishDeadLetter(ctx.System(), msg, ctx.Self(), "pool is stopped")
 			}
+			return nil
 		},
 	}
 }
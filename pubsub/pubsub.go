@@ -0,0 +1,219 @@
+package pubsub
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ioswarm/golik"
+)
+
+// Subscribe registers Subscriber, or the sender if Subscriber is nil, for
+// Topic. Topics are dot separated; '*' matches one segment and '**' any
+// number of segments. Members of the same Group receive each message in
+// turns instead of all of them.
+type Subscribe struct {
+	Topic      string
+	Group      string
+	Subscriber *golik.CloveRef
+}
+
+type SubscribeAck struct {
+	Subscribe Subscribe
+}
+
+type Unsubscribe struct {
+	Topic      string
+	Group      string
+	Subscriber *golik.CloveRef
+}
+
+type UnsubscribeAck struct {
+	Unsubscribe Unsubscribe
+}
+
+type Publish struct {
+	Topic   string
+	Payload interface{}
+}
+
+type subscription struct {
+	topic string
+	group string
+}
+
+type group struct {
+	members map[string]*golik.CloveRef
+	next    int
+}
+
+type Mediator struct {
+	name   string
+	system golik.Golik
+}
+
+func PubSub(system golik.Golik) (*Mediator, error) {
+	return NewPubSub("pubsub", system)
+}
+
+func NewPubSub(name string, system golik.Golik) (*Mediator, error) {
+	m := &Mediator{
+		name:   name,
+		system: system,
+	}
+
+	if err := system.ExecuteService(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *Mediator) Ref() (*golik.CloveRef, bool) {
+	return m.system.At("/srv/" + m.name)
+}
+
+func (m *Mediator) Publish(topic string, payload interface{}) {
+	if ref, ok := m.Ref(); ok {
+		ref.Tell(Publish{Topic: topic, Payload: payload})
+	}
+}
+
+func (m *Mediator) CreateInstance(system golik.Golik) *golik.Clove {
+	return &golik.Clove{
+		Name: m.name,
+		Receive: func(ctx golik.CloveContext) func(msg golik.Message) {
+			subscriptions := make(map[subscription]*group)
+			counts := make(map[string]int)
+
+			subscriber := func(msg golik.Message, ref *golik.CloveRef) (*golik.CloveRef, bool) {
+				if ref != nil {
+					return ref, true
+				}
+				return msg.Sender()
+			}
+
+			remove := func(sub subscription, ref *golik.CloveRef) bool {
+				g, ok := subscriptions[sub]
+				if !ok {
+					return false
+				}
+				if _, ok := g.members[ref.Path()]; !ok {
+					return false
+				}
+				delete(g.members, ref.Path())
+				if len(g.members) == 0 {
+					delete(subscriptions, sub)
+				}
+				counts[ref.Path()]--
+				if counts[ref.Path()] <= 0 {
+					delete(counts, ref.Path())
+					ctx.Unwatch(ref)
+				}
+				return true
+			}
+
+			publish := func(msg golik.Message, payload Publish) {
+				targets := make(map[string]*golik.CloveRef)
+				for sub, g := range subscriptions {
+					if !matchTopic(sub.topic, payload.Topic) {
+						continue
+					}
+					if sub.group == "" {
+						for p, ref := range g.members {
+							targets[p] = ref
+						}
+						continue
+					}
+
+					paths := make([]string, 0, len(g.members))
+					for p := range g.members {
+						paths = append(paths, p)
+					}
+					sort.Strings(paths)
+					p := paths[g.next%len(paths)]
+					g.next++
+					targets[p] = g.members[p]
+				}
+
+				if len(targets) == 0 {
+					golik.PublishDeadLetter(ctx.System(), msg, ctx.Self(), fmt.Sprintf("no subscribers for topic '%v'", payload.Topic))
+					return
+				}
+
+				delivery := msg
+				delivery.Payload = payload.Payload
+				for _, ref := range targets {
+					ref.Forward(delivery)
+				}
+			}
+
+			return func(msg golik.Message) {
+				switch payload := msg.Payload.(type) {
+				case Subscribe:
+					ref, ok := subscriber(msg, payload.Subscriber)
+					if !ok {
+						msg.Reply(fmt.Errorf("No subscriber for topic '%v'", payload.Topic))
+						return
+					}
+					sub := subscription{topic: payload.Topic, group: payload.Group}
+					g, ok := subscriptions[sub]
+					if !ok {
+						g = &group{members: make(map[string]*golik.CloveRef)}
+						subscriptions[sub] = g
+					}
+					if _, exists := g.members[ref.Path()]; !exists {
+						g.members[ref.Path()] = ref
+						counts[ref.Path()]++
+						ctx.Watch(ref)
+					}
+					ctx.Debug("'%v' subscribed to '%v'", ref.Path(), payload.Topic)
+					msg.Reply(SubscribeAck{Subscribe: payload})
+				case Unsubscribe:
+					ref, ok := subscriber(msg, payload.Subscriber)
+					if !ok {
+						msg.Reply(fmt.Errorf("No subscriber for topic '%v'", payload.Topic))
+						return
+					}
+					remove(subscription{topic: payload.Topic, group: payload.Group}, ref)
+					msg.Reply(UnsubscribeAck{Unsubscribe: payload})
+				case Publish:
+					publish(msg, payload)
+				case golik.Terminated:
+					for sub := range subscriptions {
+						remove(sub, payload.Ref)
+					}
+				case golik.ConfigChanged:
+				default:
+					golik.PublishDeadLetter(ctx.System(), msg, ctx.Self(), fmt.Sprintf("%T is no pubsub message", msg.Payload))
+				}
+			}
+		},
+	}
+}
+
+func matchTopic(pattern string, topic string) bool {
+	return matchSegments(strings.Split(pattern, "."), strings.Split(topic, "."))
+}
+
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
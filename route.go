@@ -1,6 +1,7 @@
 package golik
 
 import (
+	"context"
 	"io"
 )
 
@@ -20,6 +21,7 @@ func (v Values) Get(key string) string {
 type RouteContext interface {
 	Loggable
 	System() Golik
+	Context() context.Context
 	Header() Values
 	Params() Values
 	Queries() Values
@@ -0,0 +1,209 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+type Hashable interface {
+	HashKey() string
+}
+
+type RoutingFunc func(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef)
+
+func (f RoutingFunc) Route(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef) {
+	f(ctx, msg, routees)
+}
+
+func RoundRobin() RoutingLogic {
+	var next uint64
+	return RoutingFunc(func(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef) {
+		i := atomic.AddUint64(&next, 1) - 1
+		routees[i%uint64(len(routees))].Forward(msg)
+	})
+}
+
+func Random() RoutingLogic {
+	return RoutingFunc(func(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef) {
+		routees[rand.Intn(len(routees))].Forward(msg)
+	})
+}
+
+func SmallestMailbox() RoutingLogic {
+	return RoutingFunc(func(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef) {
+		target := routees[0]
+		min := target.Length()
+		for _, routee := range routees[1:] {
+			if l := routee.Length(); l < min {
+				target, min = routee, l
+			}
+		}
+		target.Forward(msg)
+	})
+}
+
+func Broadcast() RoutingLogic {
+	return RoutingFunc(func(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef) {
+		for _, routee := range routees {
+			routee.Forward(msg)
+		}
+	})
+}
+
+// ConsistentHashing routes messages with the same key to the same routee. The
+// key is taken from extract or, if extract is nil, from a Hashable payload.
+func ConsistentHashing(virtualNodes int, extract func(payload interface{}) (string, bool)) RoutingLogic {
+	if virtualNodes <= 0 {
+		virtualNodes = 10
+	}
+	if extract == nil {
+		extract = func(payload interface{}) (string, bool) {
+			if h, ok := payload.(Hashable); ok {
+				return h.HashKey(), true
+			}
+			return "", false
+		}
+	}
+
+	ring := &hashRing{virtualNodes: virtualNodes}
+	return RoutingFunc(func(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef) {
+		key, ok := extract(msg.Payload)
+		if !ok {
+			golik.PublishDeadLetter(ctx.System(), msg, ctx.Self(), fmt.Sprintf("no hash key for %T", msg.Payload))
+			return
+		}
+		ring.lookup(key, routees).Forward(msg)
+	})
+}
+
+func ScatterGatherFirstCompleted(within time.Duration) RoutingLogic {
+	return RoutingFunc(func(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef) {
+		go firstCompleted(msg, routees, within, 0)
+	})
+}
+
+func TailChopping(within time.Duration, interval time.Duration) RoutingLogic {
+	return RoutingFunc(func(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef) {
+		shuffled := make([]*golik.CloveRef, len(routees))
+		for i, j := range rand.Perm(len(routees)) {
+			shuffled[i] = routees[j]
+		}
+		go firstCompleted(msg, shuffled, within, interval)
+	})
+}
+
+func firstCompleted(msg golik.Message, routees []*golik.CloveRef, within time.Duration, interval time.Duration) {
+	c, cancel := context.WithTimeout(context.Background(), within)
+	defer cancel()
+
+	results := make(chan interface{}, len(routees))
+	sent, pending := 0, 0
+	send := func() {
+		f := routees[sent].AskContext(c, msg.Payload)
+		sent++
+		pending++
+		f.OnComplete(func(result interface{}, err error) {
+			if err != nil {
+				results <- err
+				return
+			}
+			results <- result
+		})
+	}
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+		send()
+	} else {
+		for sent < len(routees) {
+			send()
+		}
+	}
+
+	for {
+		select {
+		case result := <-results:
+			pending--
+			if err, ok := result.(error); ok {
+				if pending == 0 && sent == len(routees) {
+					msg.Reply(err)
+					return
+				}
+				if interval > 0 && sent < len(routees) {
+					send()
+				}
+				continue
+			}
+			msg.Reply(result)
+			return
+		case <-tick:
+			if sent < len(routees) {
+				send()
+			}
+		case <-c.Done():
+			msg.Reply(fmt.Errorf("No routee responded within %v", within))
+			return
+		}
+	}
+}
+
+type hashRing struct {
+	mutex        sync.Mutex
+	virtualNodes int
+	signature    string
+	hashes       []uint32
+	nodes        map[uint32]int
+}
+
+func hashOf(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
+
+func (r *hashRing) lookup(key string, routees []*golik.CloveRef) *golik.CloveRef {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	paths := make([]string, len(routees))
+	for i, routee := range routees {
+		paths[i] = routee.Path()
+	}
+	if signature := strings.Join(paths, ","); signature != r.signature {
+		r.signature = signature
+		r.hashes = make([]uint32, 0, len(routees)*r.virtualNodes)
+		r.nodes = make(map[uint32]int, len(routees)*r.virtualNodes)
+		for n, path := range paths {
+			for i := 0; i < r.virtualNodes; i++ {
+				h := hashOf(path + "#" + strconv.Itoa(i))
+				r.hashes = append(r.hashes, h)
+				r.nodes[h] = n
+			}
+		}
+		sort.Slice(r.hashes, func(i, j int) bool {
+			return r.hashes[i] < r.hashes[j]
+		})
+	}
+
+	h := hashOf(key)
+	i := sort.Search(len(r.hashes), func(i int) bool {
+		return r.hashes[i] >= h
+	})
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return routees[r.nodes[r.hashes[i]]]
+}
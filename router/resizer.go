@@ -0,0 +1,199 @@
+package router
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ioswarm/golik"
+)
+
+// Resizer grows a pool when every routee has at least PressureThreshold
+// messages queued and shrinks it when less than BackoffThreshold of the
+// routees are busy and fewer messages than routees were routed in the last
+// Interval. After a resize no further resize happens within Cooldown.
+type Resizer struct {
+	MinSize           int
+	MaxSize           int
+	PressureThreshold int
+	RampUpRate        float64
+	BackoffThreshold  float64
+	BackoffRate       float64
+	Interval          time.Duration
+	Cooldown          time.Duration
+}
+
+// Resized is published on the event stream of the system after every resize.
+type Resized struct {
+	Router     *golik.CloveRef
+	From       int
+	To         int
+	Pressure   int
+	Throughput int
+}
+
+// resizeState is the state of one run of a resizable pool, a restart starts
+// with a fresh one
+type resizeState struct {
+	counter    int
+	routed     int
+	lastResize time.Time
+	retiring   map[string]bool
+}
+
+// resizeTick triggers a resize of the run which scheduled it
+type resizeTick struct {
+	state *resizeState
+}
+
+func (r Resizer) withDefaults() Resizer {
+	if r.MinSize <= 0 {
+		r.MinSize = 1
+	}
+	if r.MaxSize < r.MinSize {
+		r.MaxSize = r.MinSize
+	}
+	if r.PressureThreshold <= 0 {
+		r.PressureThreshold = 1
+	}
+	if r.RampUpRate <= 0 {
+		r.RampUpRate = 0.2
+	}
+	if r.BackoffThreshold <= 0 {
+		r.BackoffThreshold = 0.3
+	}
+	if r.BackoffRate <= 0 {
+		r.BackoffRate = 0.1
+	}
+	if r.Interval <= 0 {
+		r.Interval = time.Second
+	}
+	if r.Cooldown <= 0 {
+		r.Cooldown = 10 * r.Interval
+	}
+	return r
+}
+
+func (r Resizer) capacity(size int, pressure int, throughput int) int {
+	if pressure >= size && size < r.MaxSize {
+		delta := int(math.Ceil(float64(size) * r.RampUpRate))
+		if delta < 1 {
+			delta = 1
+		}
+		if size+delta > r.MaxSize {
+			return r.MaxSize - size
+		}
+		return delta
+	}
+	if size > r.MinSize && float64(pressure)/float64(size) < r.BackoffThreshold && throughput < size {
+		delta := int(math.Floor(float64(size) * r.BackoffRate))
+		if delta < 1 {
+			delta = 1
+		}
+		if size-delta < r.MinSize {
+			return r.MinSize - size
+		}
+		return -delta
+	}
+	return 0
+}
+
+func ResizablePool(name string, resizer Resizer, logic RoutingLogic, f func() *golik.Clove) *golik.Clove {
+	resizer = resizer.withDefaults()
+
+	routees := func(ctx golik.CloveContext, state *resizeState) []*golik.CloveRef {
+		children := ctx.Children()
+		result := make([]*golik.CloveRef, 0, len(children))
+		current := make(map[string]bool, len(children))
+		for _, child := range children {
+			current[child.Path()] = true
+			if !state.retiring[child.Path()] {
+				result = append(result, child)
+			}
+		}
+		for path := range state.retiring {
+			if !current[path] {
+				delete(state.retiring, path)
+			}
+		}
+		return result
+	}
+
+	spawn := func(ctx golik.CloveContext, state *resizeState) {
+		c := f()
+		c.Name = name + "-" + strconv.Itoa(state.counter)
+		state.counter++
+		ctx.Debug("Create routee: %v", c.Name)
+		if _, err := ctx.Run(c); err != nil {
+			ctx.Error("Could not create routee '%v': %v", c.Name, err)
+		}
+	}
+
+	resize := func(ctx golik.CloveContext, state *resizeState) {
+		throughput := state.routed
+		state.routed = 0
+		if time.Since(state.lastResize) < resizer.Cooldown {
+			return
+		}
+
+		refs := routees(ctx, state)
+		pressure := 0
+		for _, ref := range refs {
+			if ref.Length() >= resizer.PressureThreshold {
+				pressure++
+			}
+		}
+
+		delta := resizer.capacity(len(refs), pressure, throughput)
+		if delta == 0 {
+			return
+		}
+
+		for i := 0; i < delta; i++ {
+			spawn(ctx, state)
+		}
+		for i := 0; i < -delta; i++ {
+			ref := refs[len(refs)-1-i]
+			state.retiring[ref.Path()] = true
+			ref.Tell(golik.PoisonPill{})
+		}
+		state.lastResize = time.Now()
+
+		event := Resized{
+			Router:     ctx.Self(),
+			From:       len(refs),
+			To:         len(refs) + delta,
+			Pressure:   pressure,
+			Throughput: throughput,
+		}
+		ctx.Info("Resize pool '%v' from %v to %v routees (pressure %v, throughput %v)", name, event.From, event.To, pressure, throughput)
+		ctx.System().EventStream().Publish(event)
+	}
+
+	return &golik.Clove{
+		Name: name,
+		// every run starts with MinSize routees; the ticks are scheduled one
+		// at a time, so the ticks of a previous run end with it
+		Receive: func(ctx golik.CloveContext) func(msg golik.Message) {
+			state := &resizeState{retiring: make(map[string]bool)}
+			for i := 0; i < resizer.MinSize; i++ {
+				spawn(ctx, state)
+			}
+			ctx.System().Scheduler().ScheduleOnce(resizer.Interval, ctx.Self(), resizeTick{state})
+
+			return func(msg golik.Message) {
+				if tick, ok := msg.Payload.(resizeTick); ok {
+					if tick.state == state {
+						resize(ctx, state)
+						ctx.System().Scheduler().ScheduleOnce(resizer.Interval, ctx.Self(), resizeTick{state})
+					}
+					return
+				}
+				if route(ctx, msg, logic, routees(ctx, state)) {
+					state.routed++
+				}
+			}
+		},
+		Async: false,
+	}
+}
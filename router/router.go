@@ -0,0 +1,76 @@
+package router
+
+import (
+	"strconv"
+
+	"github.com/ioswarm/golik"
+)
+
+type RoutingLogic interface {
+	Route(ctx golik.CloveContext, msg golik.Message, routees []*golik.CloveRef)
+}
+
+type GetRoutees struct{}
+
+type Routees struct {
+	Refs []*golik.CloveRef
+}
+
+func Pool(name string, size int, logic RoutingLogic, f func() *golik.Clove) *golik.Clove {
+	router := newRouter(name, logic, func(ctx golik.CloveContext) []*golik.CloveRef {
+		return ctx.Children()
+	})
+	router.PostStart = func(ctx golik.CloveContext) error {
+		for i := 0; i < size; i++ {
+			c := f()
+			c.Name = name + "-" + strconv.Itoa(i)
+			ctx.Debug("Create routee: %v", i)
+			if _, err := ctx.Run(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return router
+}
+
+func Group(name string, paths []string, logic RoutingLogic) *golik.Clove {
+	return newRouter(name, logic, func(ctx golik.CloveContext) []*golik.CloveRef {
+		routees := make([]*golik.CloveRef, 0, len(paths))
+		for _, path := range paths {
+			if ref, ok := ctx.System().At(path); ok {
+				routees = append(routees, ref)
+			}
+		}
+		return routees
+	})
+}
+
+func newRouter(name string, logic RoutingLogic, routees func(ctx golik.CloveContext) []*golik.CloveRef) *golik.Clove {
+	return &golik.Clove{
+		Name: name,
+		Receive: func(ctx golik.CloveContext) func(msg golik.Message) {
+			return func(msg golik.Message) {
+				route(ctx, msg, logic, routees(ctx))
+			}
+		},
+		Async: false,
+	}
+}
+
+func route(ctx golik.CloveContext, msg golik.Message, logic RoutingLogic, routees []*golik.CloveRef) bool {
+	switch msg.Payload.(type) {
+	case GetRoutees:
+		msg.Reply(Routees{Refs: routees})
+		return false
+	case golik.ConfigChanged:
+		return false
+	}
+
+	if len(routees) == 0 {
+		golik.PublishDeadLetter(ctx.System(), msg, ctx.Self(), "router has no routees")
+		return false
+	}
+	logic.Route(ctx, msg, routees)
+	return true
+}
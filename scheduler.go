@@ -0,0 +1,166 @@
+package golik
+
+import (
+	"sync"
+	"time"
+)
+
+type Cancellable interface {
+	Cancel() bool
+	IsCancelled() bool
+}
+
+type Scheduler interface {
+	ScheduleOnce(delay time.Duration, ref *CloveRef, payload interface{}) Cancellable
+	ScheduleRepeatedly(initial time.Duration, interval time.Duration, ref *CloveRef, payload interface{}) Cancellable
+	ScheduleCron(spec string, ref *CloveRef, payload interface{}) (Cancellable, error)
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{}
+}
+
+type scheduler struct{}
+
+func (s *scheduler) ScheduleOnce(delay time.Duration, ref *CloveRef, payload interface{}) Cancellable {
+	task := newScheduledTask()
+	if !task.register(ref) {
+		return task
+	}
+
+	go func() {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			if task.finish() {
+				ref.Tell(payload)
+			}
+		case <-task.cancelled:
+		}
+	}()
+
+	return task
+}
+
+func (s *scheduler) ScheduleRepeatedly(initial time.Duration, interval time.Duration, ref *CloveRef, payload interface{}) Cancellable {
+	task := newScheduledTask()
+	if !task.register(ref) {
+		return task
+	}
+
+	go func() {
+		timer := time.NewTimer(initial)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			ref.Tell(payload)
+		case <-task.cancelled:
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ref.Tell(payload)
+			case <-task.cancelled:
+				return
+			}
+		}
+	}()
+
+	return task
+}
+
+func (s *scheduler) ScheduleCron(spec string, ref *CloveRef, payload interface{}) (Cancellable, error) {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	task := newScheduledTask()
+	if !task.register(ref) {
+		return task, nil
+	}
+
+	go func() {
+		for {
+			now := time.Now()
+			next := schedule.Next(now)
+			if next.IsZero() {
+				task.finish()
+				return
+			}
+
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-timer.C:
+				ref.Tell(payload)
+			case <-task.cancelled:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+
+	return task, nil
+}
+
+func newScheduledTask() *scheduledTask {
+	return &scheduledTask{
+		cancelled: make(chan struct{}),
+	}
+}
+
+type scheduledTask struct {
+	mutex     sync.Mutex
+	cancelled chan struct{}
+	done      bool
+}
+
+func (t *scheduledTask) register(ref *CloveRef) bool {
+	if runnable, ok := ref.runnable(); ok && !runnable.addScheduledTask(t) {
+		t.Cancel()
+		return false
+	}
+	return true
+}
+
+func (t *scheduledTask) finish() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.done {
+		return false
+	}
+	t.done = true
+	return true
+}
+
+func (t *scheduledTask) isDone() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.done
+}
+
+func (t *scheduledTask) Cancel() bool {
+	if !t.finish() {
+		return false
+	}
+	close(t.cancelled)
+	return true
+}
+
+func (t *scheduledTask) IsCancelled() bool {
+	select {
+	case <-t.cancelled:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,104 @@
+package golik
+
+import (
+	"path"
+	"strings"
+)
+
+// Selection is a lazy set of cloves whose paths match a pattern. A segment
+// may contain the wildcards of path.Match, '**' matches any number of
+// segments. The pattern is evaluated each time the selection is used.
+type Selection interface {
+	Pattern() string
+	Refs() []*CloveRef
+	Tell(payload interface{})
+	Forward(msg Message)
+}
+
+type selection struct {
+	system   *coreSystem
+	pattern  string
+	segments []string
+}
+
+func newSelection(system *coreSystem, pattern string) *selection {
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return &selection{
+		system:   system,
+		pattern:  pattern,
+		segments: segments,
+	}
+}
+
+func (s *selection) Pattern() string {
+	return s.pattern
+}
+
+func (s *selection) Refs() []*CloveRef {
+	runnables := s.system.core.selectAll(s.segments, make([]*cloveRunnable, 0))
+	seen := make(map[string]bool, len(runnables))
+	result := make([]*CloveRef, 0, len(runnables))
+	for _, runnable := range runnables {
+		p := runnable.path()
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, runnable.Self())
+		}
+	}
+	return result
+}
+
+func (s *selection) Tell(payload interface{}) {
+	s.Forward(NewMessage(nil, payload))
+}
+
+func (s *selection) Forward(msg Message) {
+	refs := s.Refs()
+	if len(refs) == 0 {
+		s.system.Debug("No clove matches '%v', %T is dropped", s.pattern, msg.Payload)
+		return
+	}
+	for _, ref := range refs {
+		ref.Forward(msg)
+	}
+}
+
+func (c *cloveRunnable) liveChildren() []*cloveRunnable {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result := make([]*cloveRunnable, 0, len(c.children))
+	for _, child := range c.children {
+		if !child.isStopped() {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+func (c *cloveRunnable) selectAll(segments []string, result []*cloveRunnable) []*cloveRunnable {
+	if len(segments) == 0 {
+		return append(result, c)
+	}
+
+	segment := segments[0]
+	if segment == "**" {
+		result = c.selectAll(segments[1:], result)
+		for _, child := range c.liveChildren() {
+			result = child.selectAll(segments, result)
+		}
+		return result
+	}
+
+	for _, child := range c.liveChildren() {
+		if matched, _ := path.Match(segment, child.clove.Name); matched {
+			result = child.selectAll(segments[1:], result)
+		}
+	}
+	return result
+}
@@ -1,14 +1,30 @@
 package golik
 
 import (
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 var golikConfigFile string
 var viperInit bool
 
+var (
+	// settingsLock keeps reads of the settings apart from a reload of the
+	// config file
+	settingsLock     sync.RWMutex
+	settingsMutex    sync.Mutex
+	settingsWatching bool
+	settingsValues   map[string]interface{}
+	settingsHandlers = make(map[int]func(keys []string))
+	settingsNextID   int
+)
+
 func initSettings() {
 	if !viperInit {
 		if golikConfigFile != "" {
@@ -28,4 +44,140 @@ func initSettings() {
 
 		viperInit = true
 	}
-}
\ No newline at end of file
+	invalidateCloveSettings()
+}
+
+// ReadSettings calls f while the config file is not reloaded. Code reading
+// viper while a system watches the config file should do it within f.
+func ReadSettings(f func()) {
+	settingsLock.RLock()
+	defer settingsLock.RUnlock()
+	f()
+}
+
+func currentSettings() map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, key := range viper.AllKeys() {
+		values[key] = viper.Get(key)
+	}
+	return values
+}
+
+// reloadSettings reads the config file again and returns the changed keys.
+func reloadSettings() []string {
+	settingsLock.Lock()
+	defer settingsLock.Unlock()
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil
+	}
+	values := currentSettings()
+	keys := make([]string, 0)
+	for key, value := range values {
+		if old, ok := settingsValues[key]; !ok || !reflect.DeepEqual(old, value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range settingsValues {
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	settingsValues = values
+	invalidateCloveSettings()
+	sort.Strings(keys)
+	return keys
+}
+
+// watchConfigFile calls f whenever file is written or replaced, also when file
+// is a symlink to a file which is replaced.
+func watchConfigFile(file string, f func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	file = filepath.Clean(file)
+	realFile, _ := filepath.EvalSymlinks(file)
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				current, _ := filepath.EvalSymlinks(file)
+				written := filepath.Clean(event.Name) == file && event.Op&(fsnotify.Write|fsnotify.Create) != 0
+				if written || (current != "" && current != realFile) {
+					realFile = current
+					f()
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		return err
+	}
+	return nil
+}
+
+// watchSettings calls f with the changed keys whenever the config file is
+// modified, as long as golik.config.watch is enabled. The returned function
+// removes f again. Reads of the settings by golik are synchronized with the
+// reload, other code reading viper should use ReadSettings.
+func watchSettings(f func(keys []string)) func() {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+
+	id := settingsNextID
+	settingsNextID++
+	settingsHandlers[id] = f
+	unwatch := func() {
+		settingsMutex.Lock()
+		defer settingsMutex.Unlock()
+		delete(settingsHandlers, id)
+	}
+
+	var watch bool
+	var file string
+	ReadSettings(func() {
+		watch = viper.GetBool("golik.config.watch")
+		file = viper.ConfigFileUsed()
+		if watch && file != "" && !settingsWatching {
+			settingsValues = currentSettings()
+		}
+	})
+	if settingsWatching || !watch || file == "" {
+		return unwatch
+	}
+
+	err := watchConfigFile(file, func() {
+		keys := reloadSettings()
+		if len(keys) == 0 {
+			return
+		}
+
+		settingsMutex.Lock()
+		handlers := make([]func(keys []string), 0, len(settingsHandlers))
+		for _, handler := range settingsHandlers {
+			handlers = append(handlers, handler)
+		}
+		settingsMutex.Unlock()
+
+		for _, handler := range handlers {
+			handler(keys)
+		}
+	})
+	settingsWatching = err == nil
+	return unwatch
+}
+
+func init() {
+	viper.SetDefault("golik.config.watch", true)
+}
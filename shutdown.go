@@ -0,0 +1,189 @@
+package golik
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	PhaseStopAcceptingHTTP = "stop-accepting-http"
+	PhaseDrainServices     = "drain-services"
+	PhaseStopUserCloves    = "stop-user-cloves"
+	PhaseStopLogging       = "stop-logging"
+)
+
+var ShutdownPhases = []string{
+	PhaseStopAcceptingHTTP,
+	PhaseDrainServices,
+	PhaseStopUserCloves,
+	PhaseStopLogging,
+}
+
+// Reasons passed to TerminateWith are returned through Terminated. If a
+// phase did not complete in time, ReasonTerminate becomes ReasonIncomplete.
+const (
+	ReasonTerminate  = 0
+	ReasonIncomplete = 1
+)
+
+type ShutdownTask func(ctx context.Context) error
+
+// CoordinatedShutdown runs the registered tasks phase by phase in the order of
+// ShutdownPhases. The tasks of a phase run in parallel and have to complete
+// within golik.shutdown.phases.{phase}.timeout, falling back to
+// golik.shutdown.timeout.
+type CoordinatedShutdown interface {
+	AddTask(phase string, name string, task ShutdownTask) error
+}
+
+type namedTask struct {
+	name string
+	task ShutdownTask
+}
+
+func newCoordinatedShutdown(system Golik) *coordinatedShutdown {
+	return &coordinatedShutdown{
+		system: system,
+		tasks:  make(map[string][]namedTask),
+	}
+}
+
+type coordinatedShutdown struct {
+	system Golik
+	mutex  sync.Mutex
+	tasks  map[string][]namedTask
+}
+
+func (cs *coordinatedShutdown) AddTask(phase string, name string, task ShutdownTask) error {
+	known := false
+	for _, p := range ShutdownPhases {
+		if p == phase {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("Unknown shutdown phase '%v'", phase)
+	}
+	if task == nil {
+		return fmt.Errorf("Shutdown task '%v' is nil", name)
+	}
+
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.tasks[phase] = append(cs.tasks[phase], namedTask{name: name, task: task})
+	return nil
+}
+
+func phaseTimeout(phase string) time.Duration {
+	key := "golik.shutdown.phases." + phase + ".timeout"
+	timeout := time.Duration(0)
+	ReadSettings(func() {
+		if viper.IsSet(key) {
+			timeout = viper.GetDuration(key)
+		} else {
+			timeout = viper.GetDuration("golik.shutdown.timeout")
+		}
+	})
+	return timeout
+}
+
+func (cs *coordinatedShutdown) runPhase(phase string) bool {
+	cs.mutex.Lock()
+	tasks := make([]namedTask, len(cs.tasks[phase]))
+	copy(tasks, cs.tasks[phase])
+	cs.mutex.Unlock()
+
+	if len(tasks) == 0 {
+		return true
+	}
+
+	timeout := phaseTimeout(phase)
+	cs.system.Debug("Run shutdown phase '%v' with %v tasks (timeout %v)", phase, len(tasks), timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	results := make(chan error, len(tasks))
+	for _, nt := range tasks {
+		go func(nt namedTask) {
+			if err := nt.task(ctx); err != nil {
+				results <- fmt.Errorf("Task '%v' failed: %v", nt.name, err)
+				return
+			}
+			results <- nil
+		}(nt)
+	}
+
+	complete := true
+	for range tasks {
+		select {
+		case err := <-results:
+			if err != nil {
+				cs.system.Error("Error in shutdown phase '%v': %v", phase, err)
+				complete = false
+			}
+		case <-ctx.Done():
+			cs.system.Error("Timeout in shutdown phase '%v' after %v", phase, timeout)
+			return false
+		}
+	}
+	return complete
+}
+
+func (cs *coordinatedShutdown) run(reason int) int {
+	cs.system.Info("Shutdown with reason %v", reason)
+	complete := true
+	for _, phase := range ShutdownPhases {
+		if !cs.runPhase(phase) {
+			complete = false
+		}
+	}
+	if !complete && reason == ReasonTerminate {
+		return ReasonIncomplete
+	}
+	return reason
+}
+
+// GracefulStop sends a PoisonPill to ref, so the messages queued before it
+// are still handled, and waits until ref and its children are stopped or ctx
+// is done. If the PoisonPill could not be queued, ref is stopped immediately.
+func GracefulStop(ctx context.Context, ref *CloveRef) error {
+	stopped := ref.Request(PoisonPill{})
+	for {
+		select {
+		case result := <-stopped:
+			if _, failed := result.(error); !failed {
+				return nil
+			}
+			stopped = ref.tellSystem(Stop{}).Result()
+		case <-ctx.Done():
+			return fmt.Errorf("Clove '%v' did not stop: %v", ref.Path(), ctx.Err())
+		}
+	}
+}
+
+func stopChildren(ctx context.Context, runnable *cloveRunnable) error {
+	children := runnable.liveChildren()
+	results := make(chan error, len(children))
+	for _, child := range children {
+		go func(ref *CloveRef) {
+			results <- GracefulStop(ctx, ref)
+		}(child.Self())
+	}
+
+	var failed error
+	for range children {
+		if err := <-results; err != nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+func init() {
+	viper.SetDefault("golik.shutdown.timeout", "10s")
+}
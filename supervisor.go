@@ -0,0 +1,123 @@
+package golik
+
+import (
+	"fmt"
+	"time"
+)
+
+type Directive uint8
+
+const (
+	ResumeDirective Directive = iota
+	RestartDirective
+	StopDirective
+	EscalateDirective
+)
+
+func (d Directive) String() string {
+	switch d {
+	case ResumeDirective:
+		return "Resume"
+	case RestartDirective:
+		return "Restart"
+	case StopDirective:
+		return "Stop"
+	case EscalateDirective:
+		return "Escalate"
+	}
+	return fmt.Sprintf("Directive(%d)", uint8(d))
+}
+
+type StrategyKind uint8
+
+const (
+	OneForOne StrategyKind = iota
+	AllForOne
+)
+
+type Decider func(err error) Directive
+
+// SupervisorStrategy decides what happens to the children of a clove when
+// one of them fails. MaxRetries limits the restarts within the Within window
+// (0 means unlimited); when exceeded the failing child, or under AllForOne
+// every child, is stopped.
+type SupervisorStrategy struct {
+	Kind       StrategyKind
+	MaxRetries int
+	Within     time.Duration
+	Decider    Decider
+}
+
+func OneForOneStrategy(maxRetries int, within time.Duration, decider Decider) *SupervisorStrategy {
+	return &SupervisorStrategy{
+		Kind:       OneForOne,
+		MaxRetries: maxRetries,
+		Within:     within,
+		Decider:    decider,
+	}
+}
+
+func AllForOneStrategy(maxRetries int, within time.Duration, decider Decider) *SupervisorStrategy {
+	return &SupervisorStrategy{
+		Kind:       AllForOne,
+		MaxRetries: maxRetries,
+		Within:     within,
+		Decider:    decider,
+	}
+}
+
+func DefaultDecider(err error) Directive {
+	return RestartDirective
+}
+
+func DefaultSupervisorStrategy() *SupervisorStrategy {
+	return OneForOneStrategy(0, 0, DefaultDecider)
+}
+
+func (s *SupervisorStrategy) decide(failures map[string][]time.Time, child string, err error) Directive {
+	directive := RestartDirective
+	if s.Decider != nil {
+		directive = s.Decider(err)
+	}
+	if directive != RestartDirective || s.MaxRetries <= 0 {
+		return directive
+	}
+
+	key := child
+	if s.Kind == AllForOne {
+		key = ""
+	}
+
+	now := time.Now()
+	retries := make([]time.Time, 0, len(failures[key])+1)
+	for _, t := range failures[key] {
+		if s.Within <= 0 || now.Sub(t) <= s.Within {
+			retries = append(retries, t)
+		}
+	}
+	retries = append(retries, now)
+
+	if len(retries) > s.MaxRetries {
+		delete(failures, key)
+		return StopDirective
+	}
+	failures[key] = retries
+	return directive
+}
+
+func recoverError(r interface{}) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", r)
+}
+
+func safeCall(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoverError(r)
+		}
+	}()
+	f()
+	return nil
+}
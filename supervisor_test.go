@@ -0,0 +1,91 @@
+package golik
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("Test failure")
+
+func TestDecideWithoutRetryLimit(t *testing.T) {
+	strategy := DefaultSupervisorStrategy()
+	failures := make(map[string][]time.Time)
+	for i := 0; i < 10; i++ {
+		if directive := strategy.decide(failures, "a", errTest); directive != RestartDirective {
+			t.Fatalf("Failure %v decided %v, want restart", i+1, directive)
+		}
+	}
+	if len(failures) != 0 {
+		t.Errorf("Failures are recorded without a retry limit: %v", failures)
+	}
+}
+
+func TestDecideStopsWhenRetriesExceeded(t *testing.T) {
+	strategy := OneForOneStrategy(2, time.Minute, nil)
+	failures := make(map[string][]time.Time)
+
+	for i := 0; i < 2; i++ {
+		if directive := strategy.decide(failures, "a", errTest); directive != RestartDirective {
+			t.Fatalf("Failure %v decided %v, want restart", i+1, directive)
+		}
+	}
+	if directive := strategy.decide(failures, "b", errTest); directive != RestartDirective {
+		t.Errorf("First failure of another child decided %v, want restart", directive)
+	}
+	if directive := strategy.decide(failures, "a", errTest); directive != StopDirective {
+		t.Errorf("Failure 3 decided %v, want stop", directive)
+	}
+	if _, ok := failures["a"]; ok {
+		t.Error("Failures of a stopped child are kept")
+	}
+}
+
+func TestDecideForgetsFailuresOutsideWindow(t *testing.T) {
+	strategy := OneForOneStrategy(1, 20*time.Millisecond, nil)
+	failures := make(map[string][]time.Time)
+
+	if directive := strategy.decide(failures, "a", errTest); directive != RestartDirective {
+		t.Fatalf("Failure 1 decided %v, want restart", directive)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if directive := strategy.decide(failures, "a", errTest); directive != RestartDirective {
+		t.Errorf("Failure after the window decided %v, want restart", directive)
+	}
+	if directive := strategy.decide(failures, "a", errTest); directive != StopDirective {
+		t.Errorf("Failure within the window decided %v, want stop", directive)
+	}
+}
+
+func TestDecideAllForOneCountsAllChildren(t *testing.T) {
+	strategy := AllForOneStrategy(2, time.Minute, nil)
+	failures := make(map[string][]time.Time)
+
+	for _, child := range []string{"a", "b"} {
+		if directive := strategy.decide(failures, child, errTest); directive != RestartDirective {
+			t.Fatalf("Failure of %v decided %v, want restart", child, directive)
+		}
+	}
+	if directive := strategy.decide(failures, "c", errTest); directive != StopDirective {
+		t.Errorf("Third failure among the children decided %v, want stop", directive)
+	}
+}
+
+func TestDecideUsesDecider(t *testing.T) {
+	strategy := OneForOneStrategy(1, time.Minute, func(err error) Directive {
+		if err == errTest {
+			return ResumeDirective
+		}
+		return RestartDirective
+	})
+	failures := make(map[string][]time.Time)
+
+	for i := 0; i < 3; i++ {
+		if directive := strategy.decide(failures, "a", errTest); directive != ResumeDirective {
+			t.Fatalf("Failure %v decided %v, want resume", i+1, directive)
+		}
+	}
+	if directive := strategy.decide(failures, "a", ErrKilled); directive != RestartDirective {
+		t.Errorf("Other failure decided %v, want restart", directive)
+	}
+}
@@ -1,13 +1,16 @@
 package golik
 
 import (
+	"context"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 type Golik interface {
@@ -15,13 +18,19 @@ type Golik interface {
 	CloveExecuter
 	Name() string
 	At(path string) (*CloveRef, bool)
+	Select(pattern string) Selection
+	DeadLetters() *CloveRef
+	EventStream() EventStream
 	Terminate()
+	TerminateWith(reason int)
 	Terminated() <- chan int
+	Shutdown() CoordinatedShutdown
 
 	ExecuteService(srv Service) error
 
+	Scheduler() Scheduler
 	NewTimer(duration time.Duration, f func(time time.Time)) *time.Timer
-	NewTicker(interval time.Duration, f func(time time.Time)) *time.Ticker
+	NewTicker(interval time.Duration, f func(time time.Time)) Cancellable
 }
 
 func NewSystem(name string) (Golik, error) {
@@ -39,7 +48,9 @@ func NewSystem(name string) (Golik, error) {
 			"golik": name,
 			"hostname": hostname,
 		}),
-		exitChan: make(chan int),
+		exitChan: make(chan int, 1),
+		scheduler: newScheduler(),
+		eventStream: newEventStream(),
 	}
 
 	cc, err := newCore().execute(nil, sys)
@@ -62,6 +73,32 @@ func NewSystem(name string) (Golik, error) {
 	sys.srv = srv
 	cc.appendChild(srv)
 
+	dl, err := newDeadLetters().execute(cc, sys)
+	if err != nil {
+		return nil, err
+	}
+	sys.deadLetters = dl
+	cc.appendChild(dl)
+
+	cron, err := newCron().execute(cc, sys)
+	if err != nil {
+		return nil, err
+	}
+	cc.appendChild(cron)
+
+	sys.shutdown = newCoordinatedShutdown(sys)
+	sys.shutdown.AddTask(PhaseDrainServices, "stop-services", func(ctx context.Context) error {
+		return stopChildren(ctx, srv)
+	})
+	sys.shutdown.AddTask(PhaseStopUserCloves, "stop-user-cloves", func(ctx context.Context) error {
+		return stopChildren(ctx, usr)
+	})
+	sys.shutdown.AddTask(PhaseStopLogging, "stop-system", func(ctx context.Context) error {
+		return GracefulStop(ctx, cc.Self())
+	})
+
+	sys.unwatchSettings = watchSettings(sys.settingsChanged)
+
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
@@ -80,6 +117,12 @@ type coreSystem struct {
 	core *cloveRunnable
 	srv *cloveRunnable
 	usr *cloveRunnable
+	deadLetters *cloveRunnable
+	scheduler *scheduler
+	eventStream *eventStream
+	unwatchSettings func()
+	shutdown *coordinatedShutdown
+	terminateOnce sync.Once
 	mutex sync.Mutex
 }
 
@@ -94,21 +137,50 @@ func (sys *coreSystem) At(path string) (*CloveRef, bool) {
 	return nil, false
 }
 
+func (sys *coreSystem) Select(pattern string) Selection {
+	return newSelection(sys, pattern)
+}
+
+func (sys *coreSystem) EventStream() EventStream {
+	return sys.eventStream
+}
+
+func (sys *coreSystem) DeadLetters() *CloveRef {
+	if sys.deadLetters == nil {
+		return nil
+	}
+	return sys.deadLetters.Self()
+}
+
 func (sys *coreSystem) Terminate() {
-	go func() {
-		select {
-		case res := <- sys.core.Self().Request(Stop{}):
-			switch res.(type) {
-			case error:
-				sys.Error("Error while stoppping cloves: %v", res)
-			case Stopped:
-				sys.exitChan <- 0
-			}
-		case <- time.After(30 * time.Second): // TODO configure termination-timeout
-			sys.Error("Timeout while stopping cloves")
-			sys.exitChan <- 1
+	sys.TerminateWith(ReasonTerminate)
+}
+
+func (sys *coreSystem) TerminateWith(reason int) {
+	sys.terminateOnce.Do(func() {
+		sys.unwatchSettings()
+		go func() {
+			sys.exitChan <- sys.shutdown.run(reason)
+		}()
+	})
+}
+
+func (sys *coreSystem) Shutdown() CoordinatedShutdown {
+	return sys.shutdown
+}
+
+func (sys *coreSystem) settingsChanged(keys []string) {
+	sys.Info("Configuration changed: %v", strings.Join(keys, ", "))
+	for _, key := range keys {
+		if key == "golik.log.level" {
+			var level logrus.Level
+			ReadSettings(func() {
+				level, _ = parseLogLevel(viper.GetString(key))
+			})
+			logrus.SetLevel(level)
 		}
-	}()
+	}
+	sys.core.settingsChanged(keys)
 }
 
 func (sys *coreSystem) Terminated() <- chan int {
@@ -172,24 +244,34 @@ func (sys *coreSystem) Panic(msg string, values ...interface{}) {
 	})
 }
 
-func (sys *coreSystem) NewTimer(duration time.Duration, f func(time time.Time)) *time.Timer {
-	t := time.NewTimer(duration)
-
-	go func() {
-		f(<- t.C)
-	}()
+func (sys *coreSystem) Scheduler() Scheduler {
+	return sys.scheduler
+}
 
-	return t
+func (sys *coreSystem) NewTimer(duration time.Duration, f func(time time.Time)) *time.Timer {
+	return time.AfterFunc(duration, func() {
+		f(time.Now())
+	})
 }
 
-func (sys *coreSystem) NewTicker(interval time.Duration, f func(time time.Time)) *time.Ticker {
-	t := time.NewTicker(interval)
+func (sys *coreSystem) NewTicker(interval time.Duration, f func(time time.Time)) Cancellable {
+	task := newScheduledTask()
 
 	go func() {
-		f(<- t.C)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case tick := <-ticker.C:
+				f(tick)
+			case <-task.cancelled:
+				return
+			}
+		}
 	}()
 
-	return t
+	return task
 }
 
 